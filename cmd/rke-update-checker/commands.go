@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/start-codex/rke-update-checker/internal/display"
+	"github.com/start-codex/rke-update-checker/internal/rancher"
+)
+
+// runCommand ejecuta un subcomando
+func runCommand(ctx context.Context, client *rancher.Client, command string, args []string) {
+	switch command {
+	case "history":
+		runHistory(ctx, client, args)
+	case "plan":
+		runPlan(ctx, client, args)
+	default:
+		log.Fatalf("Unknown command %q (available: history, plan)", command)
+	}
+}
+
+// runHistory muestra el historial de revisiones de un release: history <cluster> <namespace> <release>
+func runHistory(ctx context.Context, client *rancher.Client, args []string) {
+	if len(args) != 3 {
+		log.Fatal("Usage: rke-update-checker history <cluster> <namespace> <release>")
+	}
+	clusterName, namespace, name := args[0], args[1], args[2]
+
+	cluster, err := client.FindCluster(ctx, clusterName)
+	if err != nil {
+		log.Fatalf("Error finding cluster: %v", err)
+	}
+
+	revisions, err := client.ReleaseHistory(ctx, cluster, namespace, name)
+	if err != nil {
+		log.Fatalf("Error getting release history: %v", err)
+	}
+
+	display.PrintHistory(cluster.Name, namespace, name, revisions)
+}
+
+// runPlan muestra la vista previa de la actualización de un release renderizada localmente:
+// plan <cluster> <namespace> <release> [version]
+func runPlan(ctx context.Context, client *rancher.Client, args []string) {
+	if len(args) != 3 && len(args) != 4 {
+		log.Fatal("Usage: rke-update-checker plan <cluster> <namespace> <release> [version]")
+	}
+	clusterName, namespace, name := args[0], args[1], args[2]
+
+	var targetVersion string
+	if len(args) == 4 {
+		targetVersion = args[3]
+	}
+
+	cluster, err := client.FindCluster(ctx, clusterName)
+	if err != nil {
+		log.Fatalf("Error finding cluster: %v", err)
+	}
+
+	plan, err := client.PlanUpgrade(ctx, cluster, namespace, name, targetVersion)
+	if err != nil {
+		log.Fatalf("Error planning upgrade: %v", err)
+	}
+
+	display.PrintPlan(plan)
+}
@@ -1,8 +1,14 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/start-codex/rke-update-checker/internal/display"
 	"github.com/start-codex/rke-update-checker/internal/rancher"
@@ -24,19 +30,49 @@ func main() {
 
 	// Crear configuración
 	config := &rancher.Config{
-		URL:     rancherURL,
-		Token:   token,
-		Verbose: verbose,
+		URL:                rancherURL,
+		Token:              token,
+		Verbose:            verbose,
+		PreReleaseCharts:   splitList(os.Getenv("PRERELEASE_CHARTS")),
+		PreReleaseClusters: splitList(os.Getenv("PRERELEASE_CLUSTERS")),
+		PolicyFile:         os.Getenv("POLICY_FILE"),
+		RepositoriesFile:   os.Getenv("HELM_REPOSITORIES_FILE"),
+		HelmSQLConfigFile:  os.Getenv("HELM_SQL_CONFIG"),
+		Concurrency:        envInt("CONCURRENCY", 4),
+		ClusterTimeout:     envDuration("CLUSTER_TIMEOUT", 5*time.Minute),
+		KubeconfigTimeout:  envDuration("KUBECONFIG_TIMEOUT", 30*time.Second),
+		SecretsTimeout:     envDuration("SECRETS_TIMEOUT", 2*time.Minute),
+		IndexTimeout:       envDuration("INDEX_TIMEOUT", 30*time.Second),
+		CacheDir:           os.Getenv("CHART_CACHE_DIR"),
+		CacheTTL:           envDuration("CHART_CACHE_TTL", time.Hour),
+		CheckImages:        os.Getenv("CHECK_IMAGES") == "true",
+		CheckValues:        os.Getenv("CHECK_VALUES") == "true",
+		TargetKubeVersion:  os.Getenv("TARGET_KUBE_VERSION"),
 	}
 
+	// SIGINT/SIGTERM cancelan el procesamiento y se muestran los resultados parciales;
+	// una segunda señal termina el proceso inmediatamente
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
 	// Crear cliente de Rancher
 	client, err := rancher.NewClient(config)
 	if err != nil {
 		log.Fatalf("Error creating Rancher client: %v", err)
 	}
 
+	// Subcomandos sobre un release concreto; sin argumentos se revisan todos los clusters
+	if len(os.Args) > 1 {
+		runCommand(ctx, client, os.Args[1], os.Args[2:])
+		return
+	}
+
 	// Obtener lista de clusters
-	clusters, err := client.ListClusters()
+	clusters, err := client.ListClusters(ctx)
 	if err != nil {
 		log.Fatalf("Error listing clusters: %v", err)
 	}
@@ -45,12 +81,56 @@ func main() {
 		log.Printf("Found %d clusters", len(clusters))
 	}
 
-	// Procesar todos los clusters
-	apps, err := client.ProcessAllClusters(clusters)
-	if err != nil {
-		log.Fatalf("Error processing clusters: %v", err)
+	// Procesar todos los clusters; los errores de clusters individuales no impiden mostrar el resto
+	apps, processErr := client.ProcessAllClusters(ctx, clusters)
+
+	if ctx.Err() != nil {
+		log.Printf("Interrupted: showing partial results")
 	}
 
 	// Mostrar resultados
 	display.PrintResults(apps)
-}
\ No newline at end of file
+
+	if processErr != nil {
+		log.Printf("Errors processing clusters:\n%v", processErr)
+	}
+}
+
+// envInt lee una variable de entorno entera con valor por defecto
+func envInt(name string, defaultValue int) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return defaultValue
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Fatalf("Invalid %s: %v", name, err)
+	}
+	return n
+}
+
+// envDuration lee una variable de entorno de duración (ej. 30s, 5m) con valor por defecto
+func envDuration(name string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return defaultValue
+	}
+
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Fatalf("Invalid %s: %v", name, err)
+	}
+	return d
+}
+
+// splitList separa una lista de valores separados por comas, ignorando espacios y valores vacíos
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
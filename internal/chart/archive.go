@@ -0,0 +1,162 @@
+package chart
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	helmchart "helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+)
+
+// archiveEntry contiene un chart descargado; su mutex serializa la descarga y done indica
+// que el resultado ya es definitivo
+type archiveEntry struct {
+	mu    sync.Mutex
+	done  bool
+	chart *helmchart.Chart
+	err   error
+}
+
+// DownloadChart descarga y carga el archivo de una versión publicada de un chart disponible.
+// Cada archivo se descarga una sola vez por ejecución aunque lo pidan varios clusters.
+func (f *Fetcher) DownloadChart(ctx context.Context, c Chart, chartVersion string) (*helmchart.Chart, error) {
+	key := c.Repo + " " + c.RepoURL + " " + c.Chart + " " + chartVersion
+
+	f.archivesMu.Lock()
+	entry, exists := f.archives[key]
+	if !exists {
+		entry = &archiveEntry{}
+		f.archives[key] = entry
+	}
+	f.archivesMu.Unlock()
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	if entry.done {
+		return entry.chart, entry.err
+	}
+
+	loaded, err := f.downloadChart(ctx, c, chartVersion)
+
+	// Una cancelación o un timeout son propios del cluster que pidió la descarga, así que no
+	// se guardan y el siguiente cluster vuelve a intentarlo
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return nil, err
+	}
+
+	entry.chart, entry.err, entry.done = loaded, err, true
+	return loaded, err
+}
+
+// downloadChart descarga el archivo de la versión desde el repositorio de origen del chart
+func (f *Fetcher) downloadChart(ctx context.Context, c Chart, chartVersion string) (*helmchart.Chart, error) {
+	ctx, cancel := f.withIndexTimeout(ctx)
+	defer cancel()
+
+	v, found := c.FindVersion(chartVersion)
+	if !found {
+		return nil, fmt.Errorf("version %s of chart %s not found in repository %s", chartVersion, c.Chart, c.Repo)
+	}
+
+	archive, err := f.openArchive(ctx, c, v)
+	if err != nil {
+		return nil, fmt.Errorf("downloading %s-%s: %w", c.Chart, v.Version, err)
+	}
+	defer archive.Close()
+
+	loaded, err := loader.LoadArchive(archive)
+	if err != nil {
+		return nil, fmt.Errorf("loading %s-%s: %w", c.Chart, v.Version, err)
+	}
+	return loaded, nil
+}
+
+// openArchive abre la descarga del archivo de un chart. Los charts de los repositorios
+// configurados se descargan directamente y los de los ClusterRepo a través del proxy de Rancher.
+func (f *Fetcher) openArchive(ctx context.Context, c Chart, v ChartVersion) (io.ReadCloser, error) {
+	for _, repo := range f.repositories {
+		if repo.Name == c.Repo && repo.URL == c.RepoURL {
+			return repo.openArchive(ctx, c.Chart, v)
+		}
+	}
+
+	query := url.Values{}
+	query.Set("link", "chart")
+	query.Set("chartName", c.Chart)
+	query.Set("version", v.Version)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", f.clusterRepoLink(c.Repo)+"?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+f.client.Opts.TokenKey)
+
+	return openURL(f.rancherHTTPClient(), req)
+}
+
+// openArchive abre la descarga del archivo de un chart del repositorio: desde el registry si es
+// OCI o desde la URL del índice, que puede ser relativa a la del repositorio
+func (r Repository) openArchive(ctx context.Context, name string, v ChartVersion) (io.ReadCloser, error) {
+	if r.IsOCI() {
+		refs, err := r.ociReferences()
+		if err != nil {
+			return nil, err
+		}
+		for _, ref := range refs {
+			if ref.Name() == name {
+				// Helm reemplaza '+' por '_' al publicar, porque '+' no es válido en un tag OCI
+				return r.ociClient().PullChart(ctx, ref, strings.ReplaceAll(v.Version, "+", "_"))
+			}
+		}
+		return nil, fmt.Errorf("chart %s not found in repository %s", name, r.Name)
+	}
+
+	if len(v.URLs) == 0 {
+		return nil, fmt.Errorf("no download URL in the index")
+	}
+
+	base, err := url.Parse(strings.TrimSuffix(r.URL, "/") + "/")
+	if err != nil {
+		return nil, err
+	}
+	archiveURL, err := base.Parse(v.URLs[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid chart URL %q: %w", v.URLs[0], err)
+	}
+
+	client, err := r.httpClient()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", archiveURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	// Como Helm, las credenciales solo se envían al host del repositorio
+	if archiveURL.Host == base.Host {
+		r.authorize(req)
+	}
+
+	return openURL(client, req)
+}
+
+// openURL ejecuta la petición y retorna el cuerpo de la respuesta si fue exitosa
+func openURL(client *http.Client, req *http.Request) (io.ReadCloser, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%s returned status %d", req.URL.Host, resp.StatusCode)
+	}
+	return resp.Body, nil
+}
@@ -0,0 +1,153 @@
+package chart
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// IndexCache guarda los charts de cada índice descargado para compartirlos entre los
+// clusters de una ejecución y, opcionalmente, persistirlos en disco entre ejecuciones
+type IndexCache struct {
+	dir string
+	ttl time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+}
+
+// cacheEntry es la entrada de un índice; su mutex serializa las descargas del mismo índice
+type cacheEntry struct {
+	mu sync.Mutex
+
+	// loaded indica que ya se intentó leer la entrada desde disco
+	loaded bool
+	// validated indica que el índice se descargó o revalidó durante esta ejecución
+	validated bool
+	index     cachedIndex
+}
+
+// cacheFormat identifica el formato de los charts persistidos; al cambiar, las entradas
+// guardadas por versiones anteriores se descartan en lugar de usarse incompletas
+const cacheFormat = 2
+
+// cachedIndex es el contenido persistido de una entrada de la caché
+type cachedIndex struct {
+	Key          string    `json:"key"`
+	Format       int       `json:"format"`
+	Revision     string    `json:"revision,omitempty"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"lastModified,omitempty"`
+	FetchedAt    time.Time `json:"fetchedAt"`
+	Charts       []Chart   `json:"charts"`
+}
+
+// NewIndexCache crea una caché de índices. Con dir vacío la caché solo vive en memoria;
+// ttl indica durante cuánto tiempo una entrada persistida sin revisión se usa sin revalidar.
+func NewIndexCache(dir string, ttl time.Duration) *IndexCache {
+	return &IndexCache{
+		dir:     dir,
+		ttl:     ttl,
+		entries: make(map[string]*cacheEntry),
+	}
+}
+
+// acquire retorna la entrada de la clave con su mutex tomado; el llamador debe liberarlo
+func (c *IndexCache) acquire(key string) *cacheEntry {
+	c.mu.Lock()
+	entry, exists := c.entries[key]
+	if !exists {
+		entry = &cacheEntry{index: cachedIndex{Key: key, Format: cacheFormat}}
+		c.entries[key] = entry
+	}
+	c.mu.Unlock()
+
+	entry.mu.Lock()
+	if !entry.loaded {
+		entry.loaded = true
+		c.load(entry)
+	}
+	return entry
+}
+
+// isFresh indica si la entrada puede usarse sin consultar el repositorio. Un índice ya
+// validado en esta ejecución siempre lo es; uno persistido en disco requiere que coincida
+// la revisión conocida (commit o generación del ClusterRepo) o, sin revisión, estar dentro del TTL.
+func (c *IndexCache) isFresh(entry *cacheEntry, revision string) bool {
+	switch {
+	case entry.validated:
+		return true
+	case entry.index.FetchedAt.IsZero():
+		return false
+	case revision != "":
+		return entry.index.Revision == revision
+	}
+	return time.Since(entry.index.FetchedAt) < c.ttl
+}
+
+// store actualiza la entrada con un índice recién descargado o revalidado y la persiste
+func (c *IndexCache) store(entry *cacheEntry) {
+	entry.validated = true
+	entry.index.FetchedAt = time.Now()
+	c.save(entry)
+}
+
+// load lee una entrada persistida en disco; los errores equivalen a un fallo de caché
+func (c *IndexCache) load(entry *cacheEntry) {
+	if c.dir == "" {
+		return
+	}
+
+	data, err := os.ReadFile(c.path(entry.index.Key))
+	if err != nil {
+		return
+	}
+
+	var index cachedIndex
+	if err := json.Unmarshal(data, &index); err != nil || index.Key != entry.index.Key || index.Format != cacheFormat {
+		return
+	}
+	entry.index = index
+}
+
+// save persiste una entrada en disco de forma atómica; los errores solo desactivan la persistencia
+func (c *IndexCache) save(entry *cacheEntry) {
+	if c.dir == "" {
+		return
+	}
+
+	data, err := json.Marshal(entry.index)
+	if err != nil {
+		return
+	}
+
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return
+	}
+
+	tmp, err := os.CreateTemp(c.dir, ".index-*")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return
+	}
+	if err := tmp.Close(); err != nil {
+		return
+	}
+
+	os.Rename(tmp.Name(), c.path(entry.index.Key))
+}
+
+// path retorna el archivo de la caché en disco para una clave
+func (c *IndexCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
@@ -0,0 +1,156 @@
+package chart
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// indexServer sirve un índice con ETag y Last-Modified y responde 304 a las peticiones
+// condicionales que coinciden con la versión actual
+type indexServer struct {
+	mu       sync.Mutex
+	etag     string
+	version  string
+	requests []*http.Request
+}
+
+func (s *indexServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = append(s.requests, r)
+
+	w.Header().Set("ETag", s.etag)
+	w.Header().Set("Last-Modified", "Mon, 01 Jan 2024 00:00:00 GMT")
+	if r.Header.Get("If-None-Match") == s.etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	io.WriteString(w, "entries:\n  app:\n    - name: app\n      version: "+s.version+"\n")
+}
+
+// publish cambia el contenido del índice servido
+func (s *indexServer) publish(etag, version string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.etag, s.version = etag, version
+}
+
+// lastRequest retorna la cantidad de peticiones recibidas y la última
+func (s *indexServer) lastRequest() (int, *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.requests) == 0 {
+		return 0, nil
+	}
+	return len(s.requests), s.requests[len(s.requests)-1]
+}
+
+func TestCachedCharts(t *testing.T) {
+	server := &indexServer{}
+	server.publish(`"v1"`, "1.0.0")
+	ts := httptest.NewServer(server)
+	defer ts.Close()
+
+	dir := t.TempDir()
+	logger := log.New(io.Discard, "", 0)
+
+	// fetch simula una ejecución: un fetcher nuevo sobre la caché persistida en dir
+	fetch := func(f *Fetcher, revision string) string {
+		t.Helper()
+		req, err := http.NewRequest("GET", ts.URL+"/index.yaml", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		charts, err := f.cachedCharts(ts.Client(), req, "repo", revision, logger)
+		if err != nil {
+			t.Fatalf("cachedCharts() unexpected error: %v", err)
+		}
+		if len(charts) != 1 {
+			t.Fatalf("cachedCharts() = %d charts, want 1", len(charts))
+		}
+		return charts[0].Version
+	}
+	run := func(ttl time.Duration) *Fetcher {
+		return NewFetcher(nil, nil, NewIndexCache(dir, ttl), 0)
+	}
+	expectRequests := func(want int) *http.Request {
+		t.Helper()
+		got, last := server.lastRequest()
+		if got != want {
+			t.Fatalf("index requests = %d, want %d", got, want)
+		}
+		return last
+	}
+
+	// Primera ejecución: descarga el índice y lo reutiliza entre clusters
+	first := run(time.Hour)
+	if v := fetch(first, ""); v != "1.0.0" {
+		t.Fatalf("version = %s, want 1.0.0", v)
+	}
+	if req := expectRequests(1); req.Header.Get("If-None-Match") != "" {
+		t.Errorf("first request sent If-None-Match %q", req.Header.Get("If-None-Match"))
+	}
+	fetch(first, "")
+	expectRequests(1)
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil || len(files) != 1 {
+		t.Fatalf("persisted entries = %v (%v), want 1", files, err)
+	}
+
+	// Dentro del TTL la entrada persistida se usa sin consultar el repositorio
+	if v := fetch(run(time.Hour), ""); v != "1.0.0" {
+		t.Errorf("version = %s, want 1.0.0", v)
+	}
+	expectRequests(1)
+
+	// Con el TTL vencido se revalida; el 304 reutiliza los charts de la caché
+	if v := fetch(run(0), ""); v != "1.0.0" {
+		t.Errorf("version = %s, want 1.0.0", v)
+	}
+	req := expectRequests(2)
+	if got := req.Header.Get("If-None-Match"); got != `"v1"` {
+		t.Errorf("If-None-Match = %q, want %q", got, `"v1"`)
+	}
+	if got := req.Header.Get("If-Modified-Since"); got != "Mon, 01 Jan 2024 00:00:00 GMT" {
+		t.Errorf("If-Modified-Since = %q", got)
+	}
+
+	// Un índice nuevo se descarga al vencer el TTL
+	server.publish(`"v2"`, "2.0.0")
+	if v := fetch(run(time.Hour), ""); v != "1.0.0" {
+		t.Errorf("version within TTL = %s, want cached 1.0.0", v)
+	}
+	expectRequests(2)
+	if v := fetch(run(0), ""); v != "2.0.0" {
+		t.Errorf("version after expiry = %s, want 2.0.0", v)
+	}
+	expectRequests(3)
+
+	// Con revisión, un cambio de revisión fuerza la revalidación aunque el TTL no haya vencido
+	fetch(run(time.Hour), "rev-1")
+	expectRequests(4)
+	fetch(run(time.Hour), "rev-1")
+	expectRequests(4)
+	fetch(run(time.Hour), "rev-2")
+	expectRequests(5)
+
+	// Las entradas de otro formato se descartan
+	stale := []byte(`{"key":"repo ` + ts.URL + `/index.yaml","format":1,"fetchedAt":"` + time.Now().Format(time.RFC3339) + `","charts":[]}`)
+	if err := os.WriteFile(files[0], stale, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if v := fetch(run(time.Hour), ""); v != "2.0.0" {
+		t.Errorf("version = %s, want 2.0.0", v)
+	}
+	if req := expectRequests(6); req.Header.Get("If-None-Match") != "" {
+		t.Errorf("request for a discarded entry sent If-None-Match %q", req.Header.Get("If-None-Match"))
+	}
+}
@@ -0,0 +1,47 @@
+package chart
+
+import "strings"
+
+// Channel identifica el canal de versiones del que sale la versión recomendada
+type Channel string
+
+const (
+	// ChannelStable solo considera versiones sin identificadores de pre-release
+	ChannelStable Channel = "stable"
+	// ChannelPreRelease considera también versiones alpha/beta/rc
+	ChannelPreRelease Channel = "prerelease"
+)
+
+// ChannelPolicy define qué charts y clusters aceptan versiones pre-release.
+// Por defecto todo usa el canal estable.
+type ChannelPolicy struct {
+	PreReleaseCharts   map[string]bool
+	PreReleaseClusters map[string]bool
+}
+
+// NewChannelPolicy crea una política a partir de listas de charts y clusters con pre-releases habilitadas
+func NewChannelPolicy(charts, clusters []string) ChannelPolicy {
+	return ChannelPolicy{
+		PreReleaseCharts:   toSet(charts),
+		PreReleaseClusters: toSet(clusters),
+	}
+}
+
+// ChannelFor retorna el canal que aplica a un chart instalado en un cluster
+func (p ChannelPolicy) ChannelFor(cluster, chartName string) Channel {
+	if p.PreReleaseClusters[cluster] || p.PreReleaseCharts[chartName] {
+		return ChannelPreRelease
+	}
+	return ChannelStable
+}
+
+// toSet convierte una lista en un set ignorando entradas vacías
+func toSet(items []string) map[string]bool {
+	set := make(map[string]bool, len(items))
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			set[item] = true
+		}
+	}
+	return set
+}
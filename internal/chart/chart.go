@@ -1,28 +1,177 @@
 package chart
 
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/start-codex/rke-update-checker/internal/version"
+)
+
 // Chart representa un chart disponible en los repositorios
 type Chart struct {
-	Version string   `json:"version"`
-	Repo    string   `json:"repo"`
-	Chart   string   `json:"chart"`
-	Name    string   `json:"name"`
-	Home    string   `json:"home"`
-	Sources []string `json:"sources"`
+	Version    string         `json:"version"`
+	AppVersion string         `json:"appVersion,omitempty"`
+	Repo       string         `json:"repo"`
+	RepoURL    string         `json:"repoURL,omitempty"`
+	Chart      string         `json:"chart"`
+	Name       string         `json:"name"`
+	Home       string         `json:"home"`
+	Sources    []string       `json:"sources"`
+	Versions   []ChartVersion `json:"versions"`
+}
+
+// ChartVersion representa una versión publicada de un chart en el índice del repositorio
+type ChartVersion struct {
+	Version     string    `json:"version"`
+	AppVersion  string    `json:"appVersion,omitempty"`
+	Created     time.Time `json:"created"`
+	Digest      string    `json:"digest,omitempty"`
+	Deprecated  bool      `json:"deprecated,omitempty"`
+	KubeVersion string    `json:"kubeVersion,omitempty"`
+	// URLs de descarga del archivo del chart, absolutas o relativas a la URL del repositorio
+	URLs []string `json:"urls,omitempty"`
+
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// IsPreRelease indica si la versión publicada es una pre-release
+func (v ChartVersion) IsPreRelease() bool {
+	return version.IsPreRelease(v.Version)
+}
+
+// IsHidden indica si la versión está oculta en el catálogo de Rancher
+func (v ChartVersion) IsHidden() bool {
+	return v.Annotations[AnnotationHidden] == "true"
+}
+
+// SortVersions ordena las versiones de la más reciente a la más antigua según SemVer
+func SortVersions(versions []ChartVersion) {
+	sort.SliceStable(versions, func(i, j int) bool {
+		return version.Compare(versions[i].Version, versions[j].Version) > 0
+	})
+}
+
+// Latest retorna la versión más reciente del chart dentro del canal indicado que cumple
+// todos los filtros. Asume que Versions está ordenado con SortVersions.
+func (c Chart) Latest(channel Channel, filters ...VersionFilter) (ChartVersion, bool) {
+	for _, v := range c.Versions {
+		if inChannel(v, channel) && matchesAll(v, filters) {
+			return v, true
+		}
+	}
+	return ChartVersion{}, false
+}
+
+// LatestForChannel retorna la versión más reciente del chart dentro del canal indicado
+// junto con el canal que la produjo
+func (c Chart) LatestForChannel(channel Channel, filters ...VersionFilter) (string, Channel) {
+	latest, found := c.Latest(channel, filters...)
+	if !found {
+		return "unknown", ChannelStable
+	}
+
+	if latest.IsPreRelease() {
+		return latest.Version, ChannelPreRelease
+	}
+	return latest.Version, ChannelStable
+}
+
+// FindVersion busca una versión publicada del chart
+func (c Chart) FindVersion(v string) (ChartVersion, bool) {
+	for _, published := range c.Versions {
+		if version.Compare(published.Version, v) == 0 {
+			return published, true
+		}
+	}
+	return ChartVersion{}, false
+}
+
+// IsDeprecated indica si el chart fue deprecado upstream, ya sea la versión instalada
+// o el chart completo (Helm marca un chart como deprecado en su versión más reciente)
+func (c Chart) IsDeprecated(installed string) bool {
+	if len(c.Versions) > 0 && c.Versions[0].Deprecated {
+		return true
+	}
+
+	for _, v := range c.Versions {
+		if version.Compare(v.Version, installed) == 0 {
+			return v.Deprecated
+		}
+	}
+
+	return false
 }
 
-// FindLatestVersionBySource busca la versión más reciente de un chart por matching de Sources
-func FindLatestVersionBySource(installedSources []string, chartName string, availableCharts []Chart) (string, string) {
+// VersionsBehind cuenta las versiones publicadas en el canal que son más nuevas que current
+// y cumplen los filtros
+func (c Chart) VersionsBehind(current string, channel Channel, filters ...VersionFilter) int {
+	behind := 0
+	for _, v := range c.Versions {
+		if inChannel(v, channel) && matchesAll(v, filters) && version.Compare(v.Version, current) > 0 {
+			behind++
+		}
+	}
+	return behind
+}
+
+// VersionsBetween cuenta las versiones publicadas en el canal estrictamente entre from y to
+// que cumplen los filtros
+func (c Chart) VersionsBetween(from, to string, channel Channel, filters ...VersionFilter) int {
+	between := 0
+	for _, v := range c.Versions {
+		if inChannel(v, channel) && matchesAll(v, filters) && version.Compare(v.Version, from) > 0 && version.Compare(v.Version, to) < 0 {
+			between++
+		}
+	}
+	return between
+}
+
+// LatestInMajor retorna la versión más reciente del canal dentro de la línea major de current.
+// Retorna false si no hay una versión más nueva que current en esa línea.
+func (c Chart) LatestInMajor(current string, channel Channel, filters ...VersionFilter) (ChartVersion, bool) {
+	return c.Latest(channel, append(filters, newerInLine(current, version.SameMajor))...)
+}
+
+// LatestInMinor retorna la versión más reciente del canal dentro de la línea major.minor de current.
+// Retorna false si no hay una versión más nueva que current en esa línea.
+func (c Chart) LatestInMinor(current string, channel Channel, filters ...VersionFilter) (ChartVersion, bool) {
+	return c.Latest(channel, append(filters, newerInLine(current, version.SameMinor))...)
+}
+
+// inChannel verifica si una versión pertenece al canal indicado
+func inChannel(v ChartVersion, channel Channel) bool {
+	return channel == ChannelPreRelease || !v.IsPreRelease()
+}
+
+// FindChart busca el chart disponible que corresponde a un release instalado,
+// primero por nombre y luego por matching de Sources
+func FindChart(installedSources []string, chartName string, availableCharts []Chart) (Chart, bool) {
 	chart, found := FindChartByName(availableCharts, chartName, installedSources)
-	if found {
-		return chart.Version, chart.Repo
+	if !found {
+		chart, found = FindChartBySource(availableCharts, installedSources)
 	}
+	return chart, found
+}
 
-	chart, found = FindChartBySource(availableCharts, installedSources)
-	if found {
-		return chart.Version, chart.Repo
+// FindDependency busca el chart de una dependencia declarada en un Chart.yaml, preferentemente
+// en el repositorio indicado por su URL y si no por nombre en cualquier repositorio
+func FindDependency(charts []Chart, name, repoURL string) (Chart, bool) {
+	normalized := normalizeRepoURL(repoURL)
+	if normalized != "" {
+		for _, chart := range charts {
+			if chart.Chart == name && normalizeRepoURL(chart.RepoURL) == normalized {
+				return chart, true
+			}
+		}
 	}
 
-	return "unknown", "unknown"
+	return FindChartByName(charts, name, nil)
+}
+
+// normalizeRepoURL normaliza la URL de un repositorio para compararla
+func normalizeRepoURL(url string) string {
+	return strings.ToLower(strings.TrimSuffix(strings.TrimSpace(url), "/"))
 }
 
 // FindChartByName busca un chart por nombre con estrategia de fallback
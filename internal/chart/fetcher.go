@@ -1,27 +1,39 @@
 package chart
 
 import (
+	"bufio"
+	"compress/gzip"
 	"context"
 	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log"
 	"net/http"
 	"strings"
+	"sync"
 	"time"
 
 	rancherClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"gopkg.in/yaml.v3"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
-	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/rest"
 )
 
 // Fetcher maneja la obtención de charts desde repositorios
 type Fetcher struct {
-	client  *rancherClient.Client
-	verbose bool
+	client       *rancherClient.Client
+	repositories []Repository
+	cache        *IndexCache
+	indexTimeout time.Duration
+
+	// Archivos de charts descargados durante la ejecución, compartidos entre clusters
+	archivesMu sync.Mutex
+	archives   map[string]*archiveEntry
 }
 
 // CatalogRepoResponse estructura para la respuesta de repositorios
@@ -32,30 +44,57 @@ type CatalogRepoResponse struct {
 		Links struct {
 			Index string `json:"index"`
 		} `json:"links"`
+		// Revision identifica el contenido del índice (commit o generación del ClusterRepo)
+		Revision string `json:"revision"`
+		// URL del repositorio de origen (spec.url o spec.gitRepo del ClusterRepo)
+		URL string `json:"url"`
 	} `json:"data"`
 }
 
 // HelmIndexResponse estructura para la respuesta del índice de Helm
 type HelmIndexResponse struct {
-	Entries map[string][]struct {
-		Home    string   `json:"home"`
-		Name    string   `json:"name"`
-		Version string   `json:"version"`
-		Sources []string `json:"sources"`
-	} `json:"entries"`
+	Entries map[string][]HelmIndexEntry `json:"entries" yaml:"entries"`
+}
+
+// HelmIndexEntry representa una versión de un chart dentro del índice de Helm
+type HelmIndexEntry struct {
+	Home        string    `json:"home" yaml:"home"`
+	Name        string    `json:"name" yaml:"name"`
+	Version     string    `json:"version" yaml:"version"`
+	AppVersion  string    `json:"appVersion" yaml:"appVersion"`
+	Created     time.Time `json:"created" yaml:"created"`
+	Digest      string    `json:"digest" yaml:"digest"`
+	Deprecated  bool      `json:"deprecated" yaml:"deprecated"`
+	KubeVersion string    `json:"kubeVersion" yaml:"kubeVersion"`
+	Sources     []string  `json:"sources" yaml:"sources"`
+	URLs        []string  `json:"urls" yaml:"urls"`
+
+	Annotations map[string]string `json:"annotations" yaml:"annotations"`
 }
 
-// NewFetcher crea un nuevo fetcher de charts
-func NewFetcher(client *rancherClient.Client, verbose bool) *Fetcher {
+// NewFetcher crea un nuevo fetcher de charts. Los repositorios indicados se consultan
+// directamente, además de los ClusterRepo registrados en Rancher. La caché de índices se
+// comparte entre todos los clusters que usan el fetcher (nil = sin caché) e indexTimeout
+// limita la descarga de cada índice (0 = sin límite).
+func NewFetcher(client *rancherClient.Client, repositories []Repository, cache *IndexCache, indexTimeout time.Duration) *Fetcher {
+	if cache == nil {
+		cache = NewIndexCache("", 0)
+	}
+
 	return &Fetcher{
-		client:  client,
-		verbose: verbose,
+		client:       client,
+		repositories: repositories,
+		cache:        cache,
+		indexTimeout: indexTimeout,
+		archives:     make(map[string]*archiveEntry),
 	}
 }
 
-// GetAllAvailableCharts obtiene todos los charts disponibles de todos los repositorios
-func (f *Fetcher) GetAllAvailableCharts(cluster rancherClient.Cluster) ([]Chart, error) {
-	repoData, err := f.getClusterReposWithLinks(cluster)
+// GetAllAvailableCharts obtiene todos los charts disponibles de todos los repositorios.
+// config es la configuración de acceso al cluster cuyos ClusterRepo se consultan y logger
+// recibe los mensajes verbose de ese cluster.
+func (f *Fetcher) GetAllAvailableCharts(ctx context.Context, config *rest.Config, logger *log.Logger) ([]Chart, error) {
+	repoData, err := f.getClusterReposWithLinks(ctx, config)
 	if err != nil {
 		return nil, fmt.Errorf("error getting cluster repos: %w", err)
 	}
@@ -63,85 +102,258 @@ func (f *Fetcher) GetAllAvailableCharts(cluster rancherClient.Cluster) ([]Chart,
 	var allCharts []Chart
 
 	for _, repo := range repoData.Data {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		if repo.Links.Index != "" {
-			charts, err := f.getChartsFromRepo(repo.ID, repo.Links.Index)
+			charts, err := f.getChartsFromRepo(ctx, repo.ID, repo.Links.Index, repo.Revision, logger)
 			if err != nil {
+				logger.Printf("Error fetching repository %s: %v", repo.ID, err)
 				continue
 			}
-			allCharts = append(allCharts, charts...)
+			allCharts = appendWithRepoURL(allCharts, charts, repo.URL)
+		}
+	}
+
+	// Repositorios Helm consultados sin pasar por el proxy de Rancher
+	for _, repo := range f.repositories {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		charts, err := f.getChartsFromRepository(ctx, repo, logger)
+		if err != nil {
+			logger.Printf("Error fetching repository %s: %v", repo.Name, err)
+			continue
 		}
+		allCharts = appendWithRepoURL(allCharts, charts, repo.URL)
 	}
 
 	return allCharts, nil
 }
 
+// appendWithRepoURL agrega charts indicando la URL de su repositorio. Los charts provienen de
+// la caché compartida entre clusters, así que se modifican copias y no los originales.
+func appendWithRepoURL(dst, charts []Chart, repoURL string) []Chart {
+	for _, chart := range charts {
+		chart.RepoURL = repoURL
+		dst = append(dst, chart)
+	}
+	return dst
+}
+
 // getChartsFromRepo obtiene todos los charts de un repositorio específico
-func (f *Fetcher) getChartsFromRepo(repoID, indexURL string) ([]Chart, error) {
-	client := &http.Client{
+func (f *Fetcher) getChartsFromRepo(ctx context.Context, repoID, indexURL, revision string, logger *log.Logger) ([]Chart, error) {
+	ctx, cancel := f.withIndexTimeout(ctx)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", indexURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+f.client.Opts.TokenKey)
+
+	return f.cachedCharts(f.rancherHTTPClient(), req, repoID, revision, logger)
+}
+
+// rancherHTTPClient crea el cliente HTTP con el que se consulta el proxy de catálogo de Rancher
+func (f *Fetcher) rancherHTTPClient() *http.Client {
+	return &http.Client{
 		Transport: &http.Transport{
 			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
 		},
-		Timeout: 10 * time.Second,
+	}
+}
+
+// clusterRepoLink retorna la URL base del proxy de catálogo de Rancher para un ClusterRepo
+func (f *Fetcher) clusterRepoLink(name string) string {
+	return strings.Replace(f.client.Opts.URL, "/v3", "/v1/catalog.cattle.io.clusterrepos", 1) + "/" + name
+}
+
+// cachedCharts obtiene los charts de un índice a través de la caché. Si la entrada no está
+// vigente descarga el índice con una petición condicional (If-None-Match/If-Modified-Since).
+func (f *Fetcher) cachedCharts(client *http.Client, req *http.Request, repoID, revision string, logger *log.Logger) ([]Chart, error) {
+	entry := f.cache.acquire(repoID + " " + req.URL.String())
+	defer entry.mu.Unlock()
+
+	if f.cache.isFresh(entry, revision) {
+		logger.Printf("Using cached index of repository %s", repoID)
+		return entry.index.Charts, nil
+	}
+
+	if !entry.index.FetchedAt.IsZero() {
+		if entry.index.ETag != "" {
+			req.Header.Set("If-None-Match", entry.index.ETag)
+		}
+		if entry.index.LastModified != "" {
+			req.Header.Set("If-Modified-Since", entry.index.LastModified)
+		}
 	}
 
-	req, err := http.NewRequest("GET", indexURL, nil)
+	indexResponse, header, err := fetchIndex(client, req)
+	if errors.Is(err, errNotModified) {
+		logger.Printf("Index of repository %s not modified", repoID)
+		entry.index.Revision = revision
+		f.cache.store(entry)
+		return entry.index.Charts, nil
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	req.Header.Set("Authorization", "Bearer "+f.client.Opts.TokenKey)
-	req.Header.Set("Accept", "application/json")
+	entry.index.Charts = chartsFromIndex(repoID, indexResponse)
+	entry.index.Revision = revision
+	entry.index.ETag = header.Get("ETag")
+	entry.index.LastModified = header.Get("Last-Modified")
+	f.cache.store(entry)
+
+	return entry.index.Charts, nil
+}
+
+// withIndexTimeout aplica el timeout de descarga de índices al contexto
+func (f *Fetcher) withIndexTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if f.indexTimeout > 0 {
+		return context.WithTimeout(ctx, f.indexTimeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// indexAccept acepta tanto el JSON que genera el proxy de Rancher como el YAML de los repositorios Helm
+const indexAccept = "application/json, application/yaml;q=0.9, application/x-yaml;q=0.9, text/yaml;q=0.9, */*;q=0.8"
+
+// errNotModified indica que el servidor respondió 304 a una petición condicional
+var errNotModified = errors.New("index not modified")
+
+// fetchIndex descarga y decodifica un índice de Helm en formato JSON o YAML, comprimido o no.
+// Retorna también los headers de la respuesta para revalidaciones posteriores.
+func fetchIndex(client *http.Client, req *http.Request) (*HelmIndexResponse, http.Header, error) {
+	if req.Header.Get("Accept") == "" {
+		req.Header.Set("Accept", indexAccept)
+	}
+	// Al pedir gzip explícitamente el transporte no descomprime; lo hace decodeIndex
+	req.Header.Set("Accept-Encoding", "gzip")
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified {
+		return nil, resp.Header, errNotModified
+	}
 	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("API returned status %d", resp.StatusCode)
+		return nil, nil, fmt.Errorf("API returned status %d", resp.StatusCode)
 	}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, err
+	indexResponse, err := decodeIndex(resp.Body)
+	return indexResponse, resp.Header, err
+}
+
+// decodeIndex decodifica un índice leyéndolo directamente del reader, sin copiarlo antes a un
+// buffer. Los decodificadores de JSON y YAML construyen el documento completo en memoria.
+// Detecta gzip por sus magic bytes (Content-Encoding o archivos index.yaml.gz) y
+// JSON o YAML por el primer caracter significativo.
+func decodeIndex(r io.Reader) (*HelmIndexResponse, error) {
+	reader := bufio.NewReader(r)
+
+	magic, err := reader.Peek(2)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("reading index: %w", err)
+	}
+	if len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, fmt.Errorf("gzip decompress: %w", err)
+		}
+		defer gz.Close()
+		reader = bufio.NewReader(gz)
 	}
 
 	var indexResponse HelmIndexResponse
-	if err := json.Unmarshal(body, &indexResponse); err != nil {
-		return nil, err
+	if isJSON(reader) {
+		if err := json.NewDecoder(reader).Decode(&indexResponse); err != nil {
+			return nil, fmt.Errorf("json decode: %w", err)
+		}
+	} else {
+		if err := yaml.NewDecoder(reader).Decode(&indexResponse); err != nil {
+			return nil, fmt.Errorf("yaml decode: %w", err)
+		}
 	}
 
+	return &indexResponse, nil
+}
+
+// isJSON indica si el stream empieza con un objeto JSON, ignorando espacios iniciales
+func isJSON(reader *bufio.Reader) bool {
+	for {
+		b, err := reader.ReadByte()
+		if err != nil {
+			return false
+		}
+		if b == ' ' || b == '\t' || b == '\r' || b == '\n' {
+			continue
+		}
+		reader.UnreadByte()
+		return b == '{'
+	}
+}
+
+// chartsFromIndex convierte las entradas de un índice de Helm en charts del repositorio indicado
+func chartsFromIndex(repoID string, indexResponse *HelmIndexResponse) []Chart {
 	var charts []Chart
-	for chartName, versions := range indexResponse.Entries {
-		if len(versions) > 0 {
-			v := versions[0] // Primera versión = más reciente
-			charts = append(charts, Chart{
-				Version: v.Version,
-				Repo:    repoID,
-				Chart:   chartName,
-				Name:    v.Name,
-				Home:    v.Home,
-				Sources: v.Sources,
+	for chartName, entries := range indexResponse.Entries {
+		if len(entries) == 0 {
+			continue
+		}
+
+		// El formato del índice no garantiza el orden, así que se ordenan todas las versiones
+		versions := make([]ChartVersion, 0, len(entries))
+		for _, entry := range entries {
+			versions = append(versions, ChartVersion{
+				Version:     entry.Version,
+				AppVersion:  entry.AppVersion,
+				Created:     entry.Created,
+				Digest:      entry.Digest,
+				Deprecated:  entry.Deprecated,
+				KubeVersion: entry.KubeVersion,
+				URLs:        entry.URLs,
+				Annotations: entry.Annotations,
 			})
 		}
-	}
+		SortVersions(versions)
 
-	return charts, nil
-}
+		chart := Chart{
+			Repo:     repoID,
+			Chart:    chartName,
+			Versions: versions,
+		}
 
-// getClusterReposWithLinks obtiene repositorios usando k8s.io/client-go
-func (f *Fetcher) getClusterReposWithLinks(cluster rancherClient.Cluster) (CatalogRepoResponse, error) {
-	kubeConfigAction, err := f.client.Cluster.ActionGenerateKubeconfig(&cluster)
-	if err != nil {
-		return CatalogRepoResponse{}, fmt.Errorf("error getting kubeconfig: %w", err)
-	}
+		// Metadata de la versión más reciente
+		newest := entries[0]
+		for _, entry := range entries {
+			if entry.Version == versions[0].Version {
+				newest = entry
+				break
+			}
+		}
+		chart.Name = newest.Name
+		chart.Home = newest.Home
+		chart.Sources = newest.Sources
 
-	config, err := clientcmd.RESTConfigFromKubeConfig([]byte(kubeConfigAction.Config))
-	if err != nil {
-		return CatalogRepoResponse{}, fmt.Errorf("error creating kube config: %w", err)
+		if latest, found := chart.Latest(ChannelStable, Available); found {
+			chart.Version = latest.Version
+			chart.AppVersion = latest.AppVersion
+		}
+
+		charts = append(charts, chart)
 	}
 
+	return charts
+}
+
+// getClusterReposWithLinks obtiene repositorios usando k8s.io/client-go
+func (f *Fetcher) getClusterReposWithLinks(ctx context.Context, config *rest.Config) (CatalogRepoResponse, error) {
 	dynamicClient, err := dynamic.NewForConfig(config)
 	if err != nil {
 		return CatalogRepoResponse{}, fmt.Errorf("error creating dynamic client: %w", err)
@@ -154,7 +366,7 @@ func (f *Fetcher) getClusterReposWithLinks(cluster rancherClient.Cluster) (Catal
 	}
 
 	listOptions := metav1.ListOptions{}
-	repoList, err := dynamicClient.Resource(clusterRepoGVR).List(context.Background(), listOptions)
+	repoList, err := dynamicClient.Resource(clusterRepoGVR).List(ctx, listOptions)
 	if err != nil {
 		return CatalogRepoResponse{}, fmt.Errorf("error listing clusterrepos: %w", err)
 	}
@@ -162,6 +374,29 @@ func (f *Fetcher) getClusterReposWithLinks(cluster rancherClient.Cluster) (Catal
 	return f.convertUnstructuredToRepos(repoList), nil
 }
 
+// clusterRepoRevision identifica el contenido del índice de un ClusterRepo: el commit para
+// repositorios git, o la generación y la versión del ConfigMap del índice para repositorios HTTP
+func clusterRepoRevision(item unstructured.Unstructured) string {
+	if commit, _, _ := unstructured.NestedString(item.Object, "status", "commit"); commit != "" {
+		return "commit:" + commit
+	}
+
+	configMapVersion, _, _ := unstructured.NestedString(item.Object, "status", "indexConfigMapResourceVersion")
+	if configMapVersion == "" {
+		return ""
+	}
+	return fmt.Sprintf("generation:%d/%s", item.GetGeneration(), configMapVersion)
+}
+
+// clusterRepoURL retorna la URL de origen de un ClusterRepo, ya sea un repositorio HTTP u OCI o uno git
+func clusterRepoURL(item unstructured.Unstructured) string {
+	if url, _, _ := unstructured.NestedString(item.Object, "spec", "url"); url != "" {
+		return url
+	}
+	url, _, _ := unstructured.NestedString(item.Object, "spec", "gitRepo")
+	return url
+}
+
 // convertUnstructuredToRepos convierte la respuesta Unstructured a CatalogRepoResponse
 func (f *Fetcher) convertUnstructuredToRepos(repoList *unstructured.UnstructuredList) CatalogRepoResponse {
 	var response CatalogRepoResponse
@@ -173,8 +408,7 @@ func (f *Fetcher) convertUnstructuredToRepos(repoList *unstructured.Unstructured
 		}
 
 		// Construir links basados en el patrón de Rancher
-		catalogBaseURL := strings.Replace(f.client.Opts.URL, "/v3", "/v1/catalog.cattle.io.clusterrepos", 1)
-		baseURL := catalogBaseURL + "/" + name
+		baseURL := f.clusterRepoLink(name)
 
 		repo := struct {
 			ID    string `json:"id"`
@@ -182,9 +416,15 @@ func (f *Fetcher) convertUnstructuredToRepos(repoList *unstructured.Unstructured
 			Links struct {
 				Index string `json:"index"`
 			} `json:"links"`
+			// Revision identifica el contenido del índice (commit o generación del ClusterRepo)
+			Revision string `json:"revision"`
+			// URL del repositorio de origen (spec.url o spec.gitRepo del ClusterRepo)
+			URL string `json:"url"`
 		}{
-			ID:   name,
-			Name: name,
+			ID:       name,
+			Name:     name,
+			Revision: clusterRepoRevision(item),
+			URL:      clusterRepoURL(item),
 		}
 
 		repo.Links.Index = baseURL + "?link=index"
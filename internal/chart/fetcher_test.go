@@ -0,0 +1,100 @@
+package chart
+
+import (
+	"bytes"
+	"compress/gzip"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const yamlIndex = `apiVersion: v1
+entries:
+  nginx:
+    - name: nginx
+      version: 15.1.0
+      appVersion: 1.25.2
+      kubeVersion: ">=1.23.0-0"
+      urls:
+        - charts/nginx-15.1.0.tgz
+      annotations:
+        catalog.cattle.io/rancher-version: ">= 2.8.0-0"
+    - name: nginx
+      version: 15.0.0
+      deprecated: true
+generated: "2024-01-01T00:00:00Z"
+`
+
+const jsonIndex = `
+  {"entries": {"nginx": [
+    {"name": "nginx", "version": "15.1.0", "appVersion": "1.25.2", "kubeVersion": ">=1.23.0-0",
+     "urls": ["charts/nginx-15.1.0.tgz"], "annotations": {"catalog.cattle.io/rancher-version": ">= 2.8.0-0"}},
+    {"name": "nginx", "version": "15.0.0", "deprecated": true}
+  ]}}`
+
+// gzipped comprime el contenido como lo entrega un servidor con Content-Encoding: gzip
+func gzipped(t *testing.T, content string) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write([]byte(content)); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.String()
+}
+
+func TestDecodeIndex(t *testing.T) {
+	want := map[string][]HelmIndexEntry{
+		"nginx": {
+			{
+				Name:        "nginx",
+				Version:     "15.1.0",
+				AppVersion:  "1.25.2",
+				KubeVersion: ">=1.23.0-0",
+				URLs:        []string{"charts/nginx-15.1.0.tgz"},
+				Annotations: map[string]string{AnnotationRancherVersion: ">= 2.8.0-0"},
+			},
+			{Name: "nginx", Version: "15.0.0", Deprecated: true},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		input   string
+		want    map[string][]HelmIndexEntry
+		wantErr string
+	}{
+		{name: "yaml", input: yamlIndex, want: want},
+		{name: "json with leading whitespace", input: jsonIndex, want: want},
+		{name: "gzip yaml", input: gzipped(t, yamlIndex), want: want},
+		{name: "gzip json", input: gzipped(t, jsonIndex), want: want},
+		// Un solo byte no alcanza para detectar gzip y se trata como YAML
+		{name: "single byte", input: "\x1f", wantErr: "yaml decode"},
+		{name: "truncated gzip", input: gzipped(t, jsonIndex)[:20], wantErr: "unexpected EOF"},
+		{name: "invalid gzip header", input: "\x1f\x8bnot gzip", wantErr: "gzip decompress"},
+		{name: "invalid json", input: `{"entries": [}`, wantErr: "json decode"},
+		{name: "invalid yaml", input: "entries: [nginx", wantErr: "yaml decode"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeIndex(strings.NewReader(tt.input))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("decodeIndex() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeIndex() unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got.Entries, tt.want) {
+				t.Errorf("decodeIndex() = %+v, want %+v", got.Entries, tt.want)
+			}
+		})
+	}
+}
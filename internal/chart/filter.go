@@ -0,0 +1,93 @@
+package chart
+
+import (
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+
+	"github.com/start-codex/rke-update-checker/internal/version"
+)
+
+// Anotaciones del catálogo de Rancher presentes en las entradas del índice
+const (
+	AnnotationHidden         = "catalog.cattle.io/hidden"
+	AnnotationRancherVersion = "catalog.cattle.io/rancher-version"
+	AnnotationKubeVersion    = "catalog.cattle.io/kube-version"
+)
+
+// VersionFilter decide si una versión publicada puede usarse como objetivo de actualización
+type VersionFilter func(v ChartVersion) bool
+
+// Available descarta las versiones deprecadas y las ocultas en el catálogo de Rancher
+func Available(v ChartVersion) bool {
+	return !v.Deprecated && !v.IsHidden()
+}
+
+// KubeVersionFilter acepta las versiones cuyo kubeVersion (y la anotación catalog.cattle.io/kube-version)
+// es satisfecho por la versión de Kubernetes del cluster. Con una versión de cluster desconocida
+// acepta todas las versiones.
+func KubeVersionFilter(kubeVersion string) VersionFilter {
+	return func(v ChartVersion) bool {
+		return kubeVersion == "" ||
+			(IsCompatibleRange(v.KubeVersion, kubeVersion) &&
+				IsCompatibleRange(v.Annotations[AnnotationKubeVersion], kubeVersion))
+	}
+}
+
+// RancherVersionFilter acepta las versiones cuya anotación catalog.cattle.io/rancher-version es
+// satisfecha por la versión del servidor Rancher. Con una versión desconocida o de desarrollo
+// (ver IsReleaseVersion) acepta todas, como hace Rancher.
+func RancherVersionFilter(rancherVersion string) VersionFilter {
+	release := IsReleaseVersion(rancherVersion)
+	return func(v ChartVersion) bool {
+		return !release || IsCompatibleRange(v.Annotations[AnnotationRancherVersion], rancherVersion)
+	}
+}
+
+// IsReleaseVersion indica si la versión del servidor Rancher es de una release. Las compilaciones
+// de desarrollo (v2.9-head, hashes de commit) no son semver válido o usan el sufijo head.
+func IsReleaseVersion(rancherVersion string) bool {
+	if rancherVersion == "" || strings.Contains(rancherVersion, "head") {
+		return false
+	}
+	_, err := semver.StrictNewVersion(strings.TrimPrefix(rancherVersion, "v"))
+	return err == nil
+}
+
+// IsCompatibleRange verifica si una versión cumple una restricción semver, con la misma
+// semántica que Helm usa para kubeVersion: una restricción vacía siempre se cumple y una
+// restricción o versión inválida nunca.
+func IsCompatibleRange(constraint, ver string) bool {
+	if constraint == "" {
+		return true
+	}
+
+	constraints, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return false
+	}
+
+	parsed, err := semver.NewVersion(ver)
+	if err != nil {
+		return false
+	}
+
+	return constraints.Check(parsed)
+}
+
+// matchesAll verifica si una versión cumple todos los filtros
+func matchesAll(v ChartVersion, filters []VersionFilter) bool {
+	for _, filter := range filters {
+		if !filter(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// newerInLine acepta versiones más nuevas que current dentro de la misma línea según sameLine
+func newerInLine(current string, sameLine func(a, b string) bool) VersionFilter {
+	return func(v ChartVersion) bool {
+		return sameLine(v.Version, current) && version.Compare(v.Version, current) > 0
+	}
+}
@@ -0,0 +1,31 @@
+package chart
+
+import "testing"
+
+func TestRancherVersionFilter(t *testing.T) {
+	annotated := ChartVersion{Version: "1.0.0", Annotations: map[string]string{AnnotationRancherVersion: ">= 2.8.0-0 < 2.9.0-0"}}
+	unannotated := ChartVersion{Version: "1.0.0"}
+
+	tests := []struct {
+		rancherVersion string
+		version        ChartVersion
+		want           bool
+	}{
+		{"v2.8.5", annotated, true},
+		{"v2.9.1", annotated, false},
+		{"v2.9.1", unannotated, true},
+		// Versiones desconocidas o de desarrollo no filtran
+		{"", annotated, true},
+		{"v2.9-head", annotated, true},
+		{"a1b2c3d", annotated, true},
+		{"v2.9", annotated, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rancherVersion, func(t *testing.T) {
+			if got := RancherVersionFilter(tt.rancherVersion)(tt.version); got != tt.want {
+				t.Errorf("RancherVersionFilter(%q) = %v, want %v", tt.rancherVersion, got, tt.want)
+			}
+		})
+	}
+}
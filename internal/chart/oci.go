@@ -0,0 +1,147 @@
+package chart
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/start-codex/rke-update-checker/internal/oci"
+	"github.com/start-codex/rke-update-checker/internal/version"
+)
+
+// IsOCI indica si el repositorio publica sus charts como artefactos OCI
+func (r Repository) IsOCI() bool {
+	return strings.HasPrefix(r.URL, oci.Scheme)
+}
+
+// ociClient crea un cliente de la API de distribución OCI con las credenciales del repositorio
+func (r Repository) ociClient() *oci.Client {
+	return oci.NewClient(r.ociOptions())
+}
+
+// ociOptions retorna las opciones de acceso al registry del repositorio
+func (r Repository) ociOptions() oci.Options {
+	return oci.Options{
+		Username:              r.Username,
+		Password:              r.Password,
+		Token:                 r.Token,
+		InsecureSkipTLSVerify: r.InsecureSkipTLSVerify,
+		PlainHTTP:             r.PlainHTTP,
+	}
+}
+
+// RegistryOptions retorna las opciones de acceso del primer repositorio OCI con credenciales
+// alojado en el registry indicado, para reutilizarlas en otros repositorios del mismo registry
+func RegistryOptions(repositories []Repository, registry string) (oci.Options, bool) {
+	for _, r := range repositories {
+		if !r.IsOCI() || (r.Username == "" && r.Password == "" && r.Token == "") {
+			continue
+		}
+		ref, err := oci.ParseReference(r.URL)
+		if err != nil || ref.Registry != registry {
+			continue
+		}
+		return r.ociOptions(), true
+	}
+	return oci.Options{}, false
+}
+
+// ociReferences retorna las referencias de los charts del repositorio. Sin Charts
+// explícitos, la URL apunta directamente a un chart; la raíz de un registry requiere Charts.
+func (r Repository) ociReferences() ([]oci.Reference, error) {
+	base, err := oci.ParseReference(r.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(r.Charts) == 0 {
+		if base.IsRoot() {
+			return nil, fmt.Errorf("repository %s points to the registry root %s: list its charts", r.Name, base)
+		}
+		return []oci.Reference{base}, nil
+	}
+
+	refs := make([]oci.Reference, 0, len(r.Charts))
+	for _, name := range r.Charts {
+		refs = append(refs, base.Child(name))
+	}
+	return refs, nil
+}
+
+// getChartsFromOCI obtiene las versiones de los charts de un repositorio OCI listando sus tags
+func (f *Fetcher) getChartsFromOCI(ctx context.Context, repo Repository) ([]Chart, error) {
+	refs, err := repo.ociReferences()
+	if err != nil {
+		return nil, err
+	}
+
+	client := repo.ociClient()
+
+	var charts []Chart
+	for _, ref := range refs {
+		chart, ok, err := f.cachedOCIChart(ctx, client, repo.Name, ref)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			charts = append(charts, chart)
+		}
+	}
+
+	return charts, nil
+}
+
+// cachedOCIChart obtiene las versiones de un chart OCI a través de la caché de índices.
+// Los registries no ofrecen validadores para la lista de tags, así que solo aplica el TTL.
+func (f *Fetcher) cachedOCIChart(ctx context.Context, client *oci.Client, repoID string, ref oci.Reference) (Chart, bool, error) {
+	entry := f.cache.acquire(repoID + " " + oci.Scheme + ref.String())
+	defer entry.mu.Unlock()
+
+	if !f.cache.isFresh(entry, "") {
+		tags, err := client.ListTags(ctx, ref)
+		if err != nil {
+			return Chart{}, false, err
+		}
+
+		entry.index.Charts = nil
+		if chart, ok := chartFromTags(repoID, ref.Name(), tags); ok {
+			entry.index.Charts = []Chart{chart}
+		}
+		f.cache.store(entry)
+	}
+
+	if len(entry.index.Charts) == 0 {
+		return Chart{}, false, nil
+	}
+	return entry.index.Charts[0], true, nil
+}
+
+// chartFromTags construye un chart con las versiones semver de una lista de tags OCI
+func chartFromTags(repoID, name string, tags []string) (Chart, bool) {
+	var versions []ChartVersion
+	for _, tag := range tags {
+		// Helm reemplaza '+' por '_' al publicar, porque '+' no es válido en un tag OCI
+		v := strings.ReplaceAll(tag, "_", "+")
+		if _, err := version.Parse(v); err != nil {
+			continue
+		}
+		versions = append(versions, ChartVersion{Version: v})
+	}
+
+	if len(versions) == 0 {
+		return Chart{}, false
+	}
+	SortVersions(versions)
+
+	chart := Chart{
+		Repo:     repoID,
+		Chart:    name,
+		Name:     name,
+		Versions: versions,
+	}
+	if latest, found := chart.Latest(ChannelStable); found {
+		chart.Version = latest.Version
+	}
+
+	return chart, true
+}
@@ -0,0 +1,92 @@
+package chart
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestChartFromTags(t *testing.T) {
+	tests := []struct {
+		name       string
+		tags       []string
+		wantOK     bool
+		wantLatest string
+		want       []string
+	}{
+		{
+			name:       "build metadata published with underscore",
+			tags:       []string{"1.0.0", "1.2.0_rke2r1", "1.1.0", "latest", "sha256-abc.sig"},
+			wantOK:     true,
+			wantLatest: "1.2.0+rke2r1",
+			want:       []string{"1.2.0+rke2r1", "1.1.0", "1.0.0"},
+		},
+		{
+			name:       "pre-release excluded from stable latest",
+			tags:       []string{"2.0.0-rc.1", "1.9.0"},
+			wantOK:     true,
+			wantLatest: "1.9.0",
+			want:       []string{"2.0.0-rc.1", "1.9.0"},
+		},
+		{
+			name: "no semver tags",
+			tags: []string{"latest", "main"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chart, ok := chartFromTags("repo", "app", tt.tags)
+			if ok != tt.wantOK {
+				t.Fatalf("chartFromTags() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+
+			var got []string
+			for _, v := range chart.Versions {
+				got = append(got, v.Version)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("chartFromTags() versions = %v, want %v", got, tt.want)
+			}
+			if chart.Version != tt.wantLatest {
+				t.Errorf("chartFromTags() latest = %q, want %q", chart.Version, tt.wantLatest)
+			}
+			if chart.Repo != "repo" || chart.Chart != "app" {
+				t.Errorf("chartFromTags() = %s/%s, want repo/app", chart.Repo, chart.Chart)
+			}
+		})
+	}
+}
+
+func TestRegistryOptions(t *testing.T) {
+	repositories := []Repository{
+		{Name: "http", URL: "https://registry.example.com/charts", Username: "http-user"},
+		{Name: "anonymous", URL: "oci://registry.example.com/public"},
+		{Name: "private", URL: "oci://registry.example.com/private/app", Username: "reader", Password: "secret"},
+		{Name: "ghcr", URL: "oci://ghcr.io/example", Token: "ghp_token", PlainHTTP: true},
+	}
+
+	tests := []struct {
+		registry string
+		wantOK   bool
+		want     string
+	}{
+		{"registry.example.com", true, "reader"},
+		{"ghcr.io", true, "ghp_token"},
+		{"docker.io", false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.registry, func(t *testing.T) {
+			opts, ok := RegistryOptions(repositories, tt.registry)
+			if ok != tt.wantOK {
+				t.Fatalf("RegistryOptions(%q) ok = %v, want %v", tt.registry, ok, tt.wantOK)
+			}
+			if got := opts.Username + opts.Token; got != tt.want {
+				t.Errorf("RegistryOptions(%q) credentials = %q, want %q", tt.registry, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package chart
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// RepositoryFile representa un archivo de repositorios compatible con el repositories.yaml de Helm
+type RepositoryFile struct {
+	APIVersion   string       `json:"apiVersion"`
+	Repositories []Repository `json:"repositories"`
+}
+
+// Repository representa un repositorio Helm accedido directamente por HTTP(S) o un
+// registry OCI (url oci://). Además de los campos de Helm admite Token para autenticación
+// bearer, y para OCI la lista de Charts publicados bajo la URL y PlainHTTP.
+type Repository struct {
+	Name                  string `json:"name"`
+	URL                   string `json:"url"`
+	Username              string `json:"username"`
+	Password              string `json:"password"`
+	Token                 string `json:"token"`
+	CertFile              string `json:"certFile"`
+	KeyFile               string `json:"keyFile"`
+	CAFile                string `json:"caFile"`
+	InsecureSkipTLSVerify bool   `json:"insecure_skip_tls_verify"`
+
+	Charts    []string `json:"charts"`
+	PlainHTTP bool     `json:"plainHttp"`
+}
+
+// LoadRepositoryFile lee un archivo de repositorios en formato YAML
+func LoadRepositoryFile(filename string) (*RepositoryFile, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("reading repositories file: %w", err)
+	}
+
+	var file RepositoryFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parsing repositories file: %w", err)
+	}
+
+	for i, repo := range file.Repositories {
+		if repo.Name == "" || repo.URL == "" {
+			return nil, fmt.Errorf("repository %d: name and url are required", i)
+		}
+	}
+
+	return &file, nil
+}
+
+// IndexURL retorna la URL del index.yaml del repositorio
+func (r Repository) IndexURL() string {
+	return strings.TrimSuffix(r.URL, "/") + "/index.yaml"
+}
+
+// httpClient crea un cliente HTTP con la configuración TLS del repositorio
+func (r Repository) httpClient() (*http.Client, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: r.InsecureSkipTLSVerify}
+
+	if r.CAFile != "" {
+		caData, err := os.ReadFile(r.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caData) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", r.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if r.CertFile != "" && r.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(r.CertFile, r.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsConfig,
+		},
+	}, nil
+}
+
+// authorize agrega las credenciales del repositorio a la petición
+func (r Repository) authorize(req *http.Request) {
+	switch {
+	case r.Token != "":
+		req.Header.Set("Authorization", "Bearer "+r.Token)
+	case r.Username != "" || r.Password != "":
+		req.SetBasicAuth(r.Username, r.Password)
+	}
+}
+
+// getChartsFromRepository obtiene todos los charts de un repositorio Helm accedido directamente
+func (f *Fetcher) getChartsFromRepository(ctx context.Context, repo Repository, logger *log.Logger) ([]Chart, error) {
+	ctx, cancel := f.withIndexTimeout(ctx)
+	defer cancel()
+
+	if repo.IsOCI() {
+		return f.getChartsFromOCI(ctx, repo)
+	}
+
+	client, err := repo.httpClient()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", repo.IndexURL(), nil)
+	if err != nil {
+		return nil, err
+	}
+	repo.authorize(req)
+
+	return f.cachedCharts(client, req, repo.Name, "", logger)
+}
@@ -2,9 +2,15 @@ package display
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/start-codex/rke-update-checker/internal/helm"
+	"github.com/start-codex/rke-update-checker/internal/kubeapi"
+	"github.com/start-codex/rke-update-checker/internal/manifest"
 	"github.com/start-codex/rke-update-checker/internal/rancher"
+	"github.com/start-codex/rke-update-checker/internal/version"
 )
 
 // PrintResults imprime los resultados en formato tabla
@@ -14,14 +20,43 @@ func PrintResults(apps []rancher.HelmApp) {
 		return
 	}
 
-	fmt.Println("\n" + strings.Repeat("=", 160))
-	fmt.Printf("%-15s | %-12s | %-20s | %-15s | %-20s | %-12s | %-12s | %-8s | %-15s | %s\n",
-		"CLUSTER", "NAMESPACE", "RELEASE", "REPO", "CHART", "CURRENT", "LATEST", "STATUS", "UPDATE", "SOURCES")
-	fmt.Println(strings.Repeat("=", 160))
+	fmt.Println("\n" + strings.Repeat("=", 404))
+	fmt.Printf("%-15s | %-12s | %-20s | %-15s | %-20s | %-12s | %-12s | %-12s | %-27s | %-12s | %-12s | %-12s | %-12s | %-10s | %-6s | %-7s | %-12s | %-12s | %-10s | %-10s | %-8s | %-9s | %-24s | %-9s | %-15s | %s\n",
+		"CLUSTER", "NAMESPACE", "RELEASE", "REPO", "CHART", "CURRENT", "ATTEMPTED", "LATEST", "APP VERSION", "K8S", "INSTALLABLE", "POLICY", "ALLOWED", "TYPE", "BEHIND", "BETWEEN", "IN-MINOR", "IN-MAJOR", "CHANNEL", "DEPRECATED", "STATUS", "REVISION", "HEALTH", "STORAGE", "UPDATE", "SOURCES")
+	fmt.Println(strings.Repeat("=", 404))
 
 	updatesAvailable := 0
+	policyViolations := 0
+	deprecated := 0
+	helm2Releases := 0
+	unhealthy := 0
+	appVersionChanges := 0
+	outdatedDependencies := 0
+	outdatedImages := 0
+	blockedReleases := 0
+	breakingValues := 0
+	deprecatedAPIs := 0
+	updatesByType := make(map[version.Change]int)
 
 	for _, app := range apps {
+		if app.Deprecated {
+			deprecated++
+		}
+		if app.AppUpdateType != version.ChangeNone {
+			appVersionChanges++
+		}
+		if app.Health != helm.HealthOK {
+			unhealthy++
+		}
+		if app.Release.Storage == helm.StorageTiller {
+			helm2Releases++
+		}
+		// Las actualizaciones se cuentan aunque el estado muestre otra condición, como una violación de la política
+		if app.UpdateAvailable {
+			updatesAvailable++
+			updatesByType[app.UpdateType]++
+		}
+
 		updateStatus := "✓ UP-TO-DATE"
 		if app.LatestVersion == "managed" {
 			updateStatus = "🔧 MANAGED"
@@ -29,27 +64,381 @@ func PrintResults(apps []rancher.HelmApp) {
 			updateStatus = "INTERNAL"
 		} else if app.LatestVersion == "unknown" {
 			updateStatus = "❓ NOT FOUND"
+		} else if app.PolicyConstraint != "" && !app.PolicyCompliant {
+			updateStatus = "⛔ POLICY VIOLATION"
+			policyViolations++
 		} else if app.UpdateAvailable {
 			updateStatus = "⚠ UPDATE AVAILABLE"
-			updatesAvailable++
+		} else if app.ChartOnlyUpdate {
+			updateStatus = "≈ CHART ONLY"
+		} else if app.PolicyConstraint != "" && version.IsNewer(app.CurrentVersion, app.LatestVersion) {
+			updateStatus = "📌 PINNED"
+		} else if app.BlockedBy == rancher.BlockedByRancher {
+			updateStatus = "🐮 RANCHER BLOCKED"
+		} else if app.BlockedBy == rancher.BlockedByKubernetes {
+			updateStatus = "☸ K8S BLOCKED"
 		}
 
-		fmt.Printf("%-15s | %-12s | %-20s | %-15s | %-20s | %-12s | %-12s | %-8s | %-15s | %s\n",
+		fmt.Printf("%-15s | %-12s | %-20s | %-15s | %-20s | %-12s | %-12s | %-12s | %-27s | %-12s | %-12s | %-12s | %-12s | %-10s | %-6d | %-7d | %-12s | %-12s | %-10s | %-10s | %-8s | %-9s | %-24s | %-9s | %-15s | %s\n",
 			truncateString(app.Cluster, 15),
 			truncateString(app.Release.Namespace, 12),
 			truncateString(app.Release.Name, 20),
 			truncateString(app.Release.ChartRepo, 15),
 			truncateString(app.Release.ChartName, 20),
 			truncateString(app.CurrentVersion, 12),
+			truncateString(attemptedVersion(app.Release), 12),
 			truncateString(app.LatestVersion, 12),
+			truncateString(appVersionDelta(app), 27),
+			truncateString(valueOrDash(app.KubeVersion), 12),
+			truncateString(valueOrDash(app.LatestInstallable), 12),
+			truncateString(valueOrDash(app.PolicyConstraint), 12),
+			truncateString(valueOrDash(app.LatestAllowed), 12),
+			valueOrDash(string(app.UpdateType)),
+			app.VersionsBehind,
+			app.IntermediateVersions,
+			truncateString(valueOrDash(app.LatestInMinor), 12),
+			truncateString(valueOrDash(app.LatestInMajor), 12),
+			truncateString(string(app.Channel), 10),
+			yesOrDash(app.Deprecated),
 			truncateString(app.Release.Status, 8),
+			revisions(app.Release),
+			truncateString(healthWithAge(app), 24),
+			truncateString(valueOrDash(app.Release.Storage), 9),
 			updateStatus,
 			truncateString(strings.Join(app.Release.Sources, ", "), 50),
 		)
+
+		// Dependencias (subcharts) anidadas bajo su release
+		for _, dependency := range app.Dependencies {
+			if dependency.UpdateAvailable {
+				outdatedDependencies++
+			}
+			printDependency(dependency)
+		}
+
+		// Imágenes de contenedores del manifiesto anidadas bajo su release
+		for _, image := range app.Images {
+			if image.UpdateAvailable {
+				outdatedImages++
+			}
+			printImage(image)
+		}
+
+		// Impacto de la actualización en los values del release
+		if app.Values != nil {
+			if app.Values.Breaking() {
+				breakingValues++
+			}
+			printValues(*app.Values, app.TargetVersion)
+		}
+
+		// Objetos con APIs deprecadas o eliminadas en la versión de Kubernetes objetivo
+		blocked := false
+		for _, finding := range app.APIs {
+			if finding.Blocking() {
+				blocked = true
+			} else {
+				deprecatedAPIs++
+			}
+			printAPI(finding, app.TargetKubeVersion)
+		}
+		if blocked {
+			blockedReleases++
+		}
 	}
 
 	fmt.Printf("\nTotal applications: %d\n", len(apps))
-	fmt.Printf("Updates available: %d\n", updatesAvailable)
+	fmt.Printf("Updates available: %d (major: %d, minor: %d, patch: %d, prerelease: %d)\n",
+		updatesAvailable,
+		updatesByType[version.ChangeMajor],
+		updatesByType[version.ChangeMinor],
+		updatesByType[version.ChangePatch],
+		updatesByType[version.ChangePreRelease])
+	fmt.Printf("Application version changes: %d\n", appVersionChanges)
+	fmt.Printf("Outdated dependencies: %d\n", outdatedDependencies)
+	if outdatedImages > 0 {
+		fmt.Printf("Outdated images: %d\n", outdatedImages)
+	}
+	fmt.Printf("Policy violations: %d\n", policyViolations)
+	fmt.Printf("Deprecated charts: %d\n", deprecated)
+	fmt.Printf("Unhealthy releases (failed/pending/superseded): %d\n", unhealthy)
+	fmt.Printf("Releases with breaking values for the update: %d\n", breakingValues)
+	fmt.Printf("Releases blocking the Kubernetes upgrade (removed APIs): %d\n", blockedReleases)
+	fmt.Printf("Objects with deprecated APIs: %d\n", deprecatedAPIs)
+	if helm2Releases > 0 {
+		fmt.Printf("Helm 2 (Tiller) releases pending migration: %d (migrate with helm 2to3)\n", helm2Releases)
+	}
+}
+
+// printDependency imprime una dependencia anidada bajo la fila de su release
+func printDependency(dependency rancher.DependencyUpdate) {
+	name := dependency.Dependency.Name
+	if dependency.Dependency.Alias != "" {
+		name += " (" + dependency.Dependency.Alias + ")"
+	}
+
+	status := "✓ UP-TO-DATE"
+	switch {
+	case dependency.LatestVersion == "local":
+		status = "LOCAL"
+	case dependency.LatestVersion == "unknown":
+		status = "❓ NOT FOUND"
+	case dependency.UpdateAvailable:
+		status = "⚠ UPDATE AVAILABLE"
+	case !dependency.Dependency.Embedded:
+		// Helm no empaqueta en el release las dependencias deshabilitadas por condition/tags
+		status = "DISABLED"
+	}
+
+	fmt.Printf("%-15s |   └─ %-30s | %-12s | %-12s | %-10s | %-15s | %-18s | %s\n",
+		"",
+		truncateString(name, 30),
+		truncateString(dependency.Dependency.Version, 12),
+		truncateString(dependency.LatestVersion, 12),
+		valueOrDash(string(dependency.UpdateType)),
+		truncateString(dependency.Repo, 15),
+		status,
+		truncateString(valueOrDash(dependency.Dependency.Repository), 50),
+	)
+}
+
+// printImage imprime una imagen de contenedor anidada bajo la fila de su release
+func printImage(image rancher.ImageUpdate) {
+	status := "✓ UP-TO-DATE"
+	switch {
+	case image.LatestTag == "-":
+		status = "UNVERSIONED"
+	case image.AuthRequired:
+		status = "🔒 AUTH REQUIRED"
+	case image.LatestTag == "unknown":
+		status = "❓ NOT FOUND"
+	case image.UpdateAvailable:
+		status = "⚠ UPDATE AVAILABLE"
+	}
+
+	fmt.Printf("%-15s |   └─ image %-24s | %-12s | %-12s | %-10s | %-30s | %-18s | %s\n",
+		"",
+		truncateString(valueOrDash(image.Repository), 24),
+		truncateString(valueOrDash(image.Tag), 12),
+		truncateString(image.LatestTag, 12),
+		valueOrDash(string(image.UpdateType)),
+		truncateString(image.Image.Object+"/"+image.Image.Container, 30),
+		status,
+		image.Image.Image,
+	)
+}
+
+// printValues imprime el resumen de cambios en los values por defecto de la versión objetivo y,
+// debajo, las claves eliminadas que el release todavía define y los errores de validación del schema
+func printValues(drift helm.ValuesDrift, targetVersion string) {
+	status := "✓ COMPATIBLE"
+	if drift.Breaking() {
+		status = "✗ BREAKING"
+	}
+
+	fmt.Printf("%-15s |   └─ values → %-20s | +%-4d -%-4d ~%-4d | %-18s | added, removed and changed defaults\n",
+		"",
+		truncateString(targetVersion, 20),
+		len(drift.Added),
+		len(drift.Removed),
+		len(drift.Changed),
+		status,
+	)
+
+	for _, key := range drift.Overridden {
+		detail := "removed, still set in the release values"
+		if renamed, ok := drift.Renamed[key]; ok {
+			detail = "renamed to " + renamed + ", still set in the release values"
+		}
+		fmt.Printf("%-15s |        ✗ %-40s | %s\n", "", truncateString(key, 40), detail)
+	}
+	for _, schemaErr := range drift.SchemaErrors {
+		fmt.Printf("%-15s |        ✗ schema %s\n", "", schemaErr)
+	}
+}
+
+// printAPI imprime un objeto con un apiVersion deprecado anidado bajo la fila de su release
+func printAPI(finding kubeapi.Finding, targetKubeVersion string) {
+	status := "⚠ DEPRECATED IN " + finding.DeprecatedIn
+	if finding.Blocking() {
+		status = "✗ REMOVED IN " + finding.RemovedIn
+	}
+
+	fmt.Printf("%-15s |   └─ api %-26s | %-30s | %-30s | %-18s | target %s\n",
+		"",
+		truncateString(finding.APIVersion, 26),
+		truncateString(finding.Object.String(), 30),
+		truncateString(valueOrDash(finding.Replacement), 30),
+		status,
+		targetKubeVersion,
+	)
+}
+
+// PrintHistory imprime el historial de revisiones de un release en formato tabla
+func PrintHistory(cluster, namespace, name string, revisions []helm.Revision) {
+	fmt.Printf("\nHistory of %s/%s in cluster %s\n", namespace, name, cluster)
+	fmt.Println(strings.Repeat("=", 176))
+	fmt.Printf("%-8s | %-20s | %-12s | %-12s | %-16s | %-19s | %-19s | %-9s | %s\n",
+		"REVISION", "CHART", "VERSION", "APP VERSION", "STATUS", "FIRST DEPLOYED", "LAST DEPLOYED", "STORAGE", "DESCRIPTION")
+	fmt.Println(strings.Repeat("=", 176))
+
+	for _, rev := range revisions {
+		fmt.Printf("%-8d | %-20s | %-12s | %-12s | %-16s | %-19s | %-19s | %-9s | %s\n",
+			rev.Revision,
+			truncateString(rev.ChartName, 20),
+			truncateString(rev.ChartVersion, 12),
+			truncateString(valueOrDash(rev.AppVersion), 12),
+			truncateString(rev.Status, 16),
+			formatTime(rev.FirstDeployed),
+			formatTime(rev.LastDeployed),
+			valueOrDash(rev.Storage),
+			truncateString(valueOrDash(rev.Description), 50),
+		)
+	}
+
+	fmt.Printf("\nTotal revisions: %d\n", len(revisions))
+}
+
+// diffContext es la cantidad de líneas sin cambios que se muestran alrededor de cada cambio
+const diffContext = 3
+
+// PrintPlan imprime la vista previa de la actualización de un release como un diff por objeto
+func PrintPlan(plan *rancher.Plan) {
+	rel := plan.Release
+	fmt.Printf("\nUpgrade plan for %s/%s in cluster %s: %s %s → %s (app %s → %s)\n",
+		rel.Namespace, rel.Name, plan.Cluster, rel.ChartName, plan.CurrentVersion, plan.TargetVersion,
+		valueOrDash(plan.CurrentAppVersion), valueOrDash(plan.TargetAppVersion))
+	fmt.Println(strings.Repeat("=", 120))
+
+	added, removed, modified := 0, 0, 0
+	for _, change := range plan.Changes {
+		symbol := "~"
+		switch change.Type {
+		case manifest.ChangeAdded:
+			symbol = "+"
+			added++
+		case manifest.ChangeRemoved:
+			symbol = "-"
+			removed++
+		default:
+			modified++
+		}
+
+		object := change.Object.String()
+		if change.Object.Namespace != "" {
+			object = change.Object.Kind + "/" + change.Object.Namespace + "/" + change.Object.Name
+		}
+		fmt.Printf("\n%s %s (%s)\n", symbol, object, change.Type)
+		printDiffLines(change.Lines)
+	}
+
+	if len(plan.SchemaErrors) > 0 {
+		fmt.Println("\nValues do not match the values.schema.json of the target version:")
+		for _, schemaErr := range plan.SchemaErrors {
+			fmt.Printf("  ✗ %s\n", schemaErr)
+		}
+	}
+
+	fmt.Printf("\nObjects: %d added, %d removed, %d modified, %d unchanged\n", added, removed, modified, plan.Unchanged)
+}
+
+// printDiffLines imprime las líneas cambiadas de un diff con diffContext líneas de contexto,
+// separando con "..." los tramos sin cambios omitidos
+func printDiffLines(lines []manifest.DiffLine) {
+	visible := make([]bool, len(lines))
+	for i, line := range lines {
+		if line.Op == manifest.DiffEqual {
+			continue
+		}
+		for j := max(0, i-diffContext); j <= min(len(lines)-1, i+diffContext); j++ {
+			visible[j] = true
+		}
+	}
+
+	skipped := false
+	for i, line := range lines {
+		if !visible[i] {
+			skipped = true
+			continue
+		}
+		if skipped {
+			fmt.Println("    ...")
+			skipped = false
+		}
+		fmt.Printf("  %c %s\n", line.Op, line.Text)
+	}
+	if skipped {
+		fmt.Println("    ...")
+	}
+}
+
+// formatTime formatea un instante en hora local, o "-" si no se conoce
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04:05")
+}
+
+// appVersionDelta muestra la versión de la aplicación instalada y, si la versión objetivo
+// la cambia, la nueva, ej. "1.25.3 -> 1.27.1"
+func appVersionDelta(app rancher.HelmApp) string {
+	if app.LatestAppVersion == "" || app.LatestAppVersion == app.CurrentAppVersion {
+		return valueOrDash(app.CurrentAppVersion)
+	}
+	return fmt.Sprintf("%s -> %s", valueOrDash(app.CurrentAppVersion), app.LatestAppVersion)
+}
+
+// attemptedVersion retorna la versión de chart de la última revisión cuando no es la desplegada
+func attemptedVersion(rel helm.Release) string {
+	if rel.Revision == rel.DeployedRevision {
+		return "-"
+	}
+	return rel.Version
+}
+
+// revisions retorna la última revisión desplegada y la última revisión, ej. "4/5" o "-/1"
+func revisions(rel helm.Release) string {
+	deployed := "-"
+	if rel.DeployedRevision > 0 {
+		deployed = strconv.Itoa(rel.DeployedRevision)
+	}
+	return fmt.Sprintf("%s/%d", deployed, rel.Revision)
+}
+
+// healthWithAge retorna el estado del release junto al tiempo que lleva en él, ej. "failed-upgrade (3d4h)"
+func healthWithAge(app rancher.HelmApp) string {
+	if app.Health == helm.HealthOK || app.Release.StatusSince.IsZero() {
+		return string(app.Health)
+	}
+	return fmt.Sprintf("%s (%s)", app.Health, formatAge(time.Since(app.Release.StatusSince)))
+}
+
+// formatAge formatea una duración con la precisión de kubectl: días y horas, horas y minutos o minutos
+func formatAge(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd%dh", int(d.Hours())/24, int(d.Hours())%24)
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh%dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+	return fmt.Sprintf("%dm", int(d.Minutes()))
+}
+
+// yesOrDash retorna "yes" o "-" según un booleano
+func yesOrDash(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "-"
+}
+
+// valueOrDash retorna "-" para valores vacíos
+func valueOrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
 }
 
 // truncateString trunca una string a una longitud máxima
@@ -0,0 +1,80 @@
+package helm
+
+import (
+	"sort"
+
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+// Dependency representa una dependencia (subchart) del chart de un release
+type Dependency struct {
+	Name       string
+	Alias      string
+	Repository string
+	// Version es la versión del subchart empaquetado en el release o, si no está
+	// embebido, la restricción declarada en el Chart.yaml
+	Version  string
+	Embedded bool
+}
+
+// ExtractDependencies combina las dependencias declaradas en el Chart.yaml con los
+// subcharts embebidos en el chart del release. Los subcharts embebidos sin declaración
+// (ej. charts vendorizados o de Helm 2) también se incluyen.
+func ExtractDependencies(c *chart.Chart) []Dependency {
+	if c == nil {
+		return nil
+	}
+
+	// Helm renombra los subcharts con alias, así que se indexan por su nombre final
+	embedded := make(map[string]*chart.Chart)
+	for _, subchart := range c.Dependencies() {
+		if subchart.Metadata != nil {
+			embedded[subchart.Metadata.Name] = subchart
+		}
+	}
+
+	var dependencies []Dependency
+	used := make(map[string]bool)
+
+	if c.Metadata != nil {
+		for _, declared := range c.Metadata.Dependencies {
+			if declared == nil {
+				continue
+			}
+
+			dependency := Dependency{
+				Name:       declared.Name,
+				Alias:      declared.Alias,
+				Repository: declared.Repository,
+				Version:    declared.Version,
+			}
+
+			for _, name := range []string{declared.Alias, declared.Name} {
+				if subchart, ok := embedded[name]; ok && name != "" {
+					dependency.Version = subchart.Metadata.Version
+					dependency.Embedded = true
+					used[name] = true
+					break
+				}
+			}
+
+			dependencies = append(dependencies, dependency)
+		}
+	}
+
+	var undeclared []Dependency
+	for name, subchart := range embedded {
+		if !used[name] {
+			undeclared = append(undeclared, Dependency{
+				Name:     name,
+				Version:  subchart.Metadata.Version,
+				Embedded: true,
+			})
+		}
+	}
+	sort.Slice(undeclared, func(i, j int) bool {
+		return undeclared[i].Name < undeclared[j].Name
+	})
+
+	return append(dependencies, undeclared...)
+}
@@ -0,0 +1,71 @@
+package helm
+
+import (
+	"time"
+
+	"helm.sh/helm/v3/pkg/release"
+)
+
+// StuckPendingAfter es el tiempo a partir del cual un release en estado pending se considera atascado
+const StuckPendingAfter = 15 * time.Minute
+
+// Health clasifica el estado de un release según su última revisión y la última desplegada
+type Health string
+
+const (
+	HealthOK Health = "ok"
+	// La última revisión está pending-install/upgrade/rollback desde hace poco (operación en curso)
+	HealthPending Health = "pending"
+	// La última revisión lleva más de StuckPendingAfter en estado pending
+	HealthStuckPending Health = "stuck-pending"
+	// Falló una actualización: el workload sigue en una revisión desplegada anterior
+	HealthFailedUpgrade Health = "failed-upgrade"
+	// Falló la instalación y no hay ninguna revisión desplegada
+	HealthFailedInstall Health = "failed-install"
+	// La última revisión fue reemplazada pero ninguna está desplegada
+	HealthSuperseded Health = "superseded"
+	// El release se está desinstalando o fue desinstalado conservando su historial
+	HealthUninstalled Health = "uninstalled"
+	HealthUnknown     Health = "unknown"
+)
+
+// InstalledVersion retorna la versión del chart que está corriendo: la de la última revisión
+// desplegada o, si no hay ninguna, la de la última revisión
+func (r *Release) InstalledVersion() string {
+	if r.DeployedRevision > 0 {
+		return r.DeployedVersion
+	}
+	return r.Version
+}
+
+// InstalledAppVersion retorna la versión de la aplicación que está corriendo, con el mismo
+// criterio que InstalledVersion
+func (r *Release) InstalledAppVersion() string {
+	if r.DeployedRevision > 0 {
+		return r.DeployedAppVersion
+	}
+	return r.AppVersion
+}
+
+// Health clasifica el estado del release en el momento indicado
+func (r *Release) Health(now time.Time) Health {
+	switch release.Status(r.Status) {
+	case release.StatusDeployed:
+		return HealthOK
+	case release.StatusPendingInstall, release.StatusPendingUpgrade, release.StatusPendingRollback:
+		if !r.StatusSince.IsZero() && now.Sub(r.StatusSince) < StuckPendingAfter {
+			return HealthPending
+		}
+		return HealthStuckPending
+	case release.StatusFailed:
+		if r.DeployedRevision > 0 {
+			return HealthFailedUpgrade
+		}
+		return HealthFailedInstall
+	case release.StatusSuperseded:
+		return HealthSuperseded
+	case release.StatusUninstalling, release.StatusUninstalled:
+		return HealthUninstalled
+	}
+	return HealthUnknown
+}
@@ -0,0 +1,51 @@
+package helm
+
+import (
+	"sort"
+	"time"
+
+	"helm.sh/helm/v3/pkg/release"
+)
+
+// Revision representa una revisión del historial de un release
+type Revision struct {
+	Revision      int
+	ChartName     string
+	ChartVersion  string
+	AppVersion    string
+	Status        string
+	Description   string
+	FirstDeployed time.Time
+	LastDeployed  time.Time
+	Storage       string
+}
+
+// NewRevision extrae la información de historial de una revisión decodificada
+func NewRevision(rel *release.Release, storage string) Revision {
+	revision := Revision{
+		Revision: rel.Version,
+		Storage:  storage,
+	}
+
+	if rel.Chart != nil && rel.Chart.Metadata != nil {
+		revision.ChartName = rel.Chart.Metadata.Name
+		revision.ChartVersion = rel.Chart.Metadata.Version
+		revision.AppVersion = rel.Chart.Metadata.AppVersion
+	}
+
+	if rel.Info != nil {
+		revision.Status = string(rel.Info.Status)
+		revision.Description = rel.Info.Description
+		revision.FirstDeployed = rel.Info.FirstDeployed.Time
+		revision.LastDeployed = rel.Info.LastDeployed.Time
+	}
+
+	return revision
+}
+
+// SortRevisions ordena el historial de la revisión más antigua a la más reciente
+func SortRevisions(revisions []Revision) {
+	sort.SliceStable(revisions, func(i, j int) bool {
+		return revisions[i].Revision < revisions[j].Revision
+	})
+}
@@ -0,0 +1,141 @@
+package helm
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	"helm.sh/helm/v3/pkg/release"
+)
+
+// Drivers de almacenamiento de Helm en los que se descubren releases
+const (
+	StorageSecret    = "secret"
+	StorageConfigMap = "configmap"
+	StorageSQL       = "sql"
+	// StorageTiller identifica los releases de Helm 2 guardados por Tiller
+	StorageTiller = "helm2"
+)
+
+// Record representa una revisión almacenada de un release tal como la describen las etiquetas
+// del driver de almacenamiento de Helm (name, version, status, modifiedAt), sin decodificar
+type Record struct {
+	// Key es el nombre del objeto que almacena la revisión (ej. sh.helm.release.v1.app.v3)
+	Key        string
+	Name       string
+	Namespace  string
+	Revision   int
+	Status     string
+	ModifiedAt int64
+	// Storage es el driver de almacenamiento del que proviene la revisión
+	Storage string
+
+	// Data contiene el release codificado tal como lo guarda Helm
+	Data string
+}
+
+// NewRecord crea el registro de una revisión a partir de las etiquetas de su objeto de
+// almacenamiento. Si las etiquetas no identifican el release y la revisión, decodifica el contenido.
+func NewRecord(key, namespace string, labels map[string]string, data, storage string) (Record, error) {
+	record := Record{
+		Key:       key,
+		Name:      labels["name"],
+		Namespace: namespace,
+		Status:    labels["status"],
+		Storage:   storage,
+		Data:      data,
+	}
+
+	revision, err := strconv.Atoi(labels["version"])
+	if record.Name != "" && err == nil {
+		record.Revision = revision
+		record.ModifiedAt, _ = strconv.ParseInt(labels["modifiedAt"], 10, 64)
+		return record, nil
+	}
+
+	rel, err := record.Decode()
+	if err != nil {
+		return Record{}, fmt.Errorf("decoding %s: %w", key, err)
+	}
+
+	record.Name = rel.Name
+	record.Revision = rel.Version
+	if rel.Info != nil {
+		record.Status = string(rel.Info.Status)
+		record.ModifiedAt = rel.Info.LastDeployed.Unix()
+	}
+	return record, nil
+}
+
+// Decode decodifica el release guardado en la revisión según su driver de almacenamiento
+func (r Record) Decode() (*release.Release, error) {
+	if r.Storage == StorageTiller {
+		return DecodeTillerRelease(r.Data)
+	}
+	return DecodeRelease(r.Data)
+}
+
+// IsDeployed indica si la revisión es la desplegada actualmente
+func (r Record) IsDeployed() bool {
+	return r.Status == string(release.StatusDeployed)
+}
+
+// newerThan indica si la revisión es posterior a otra del mismo release. Las revisiones
+// repetidas se desempatan por fecha de modificación y luego por nombre del objeto.
+func (r Record) newerThan(o Record) bool {
+	if r.Revision != o.Revision {
+		return r.Revision > o.Revision
+	}
+	if r.ModifiedAt != o.ModifiedAt {
+		return r.ModifiedAt > o.ModifiedAt
+	}
+	return r.Key > o.Key
+}
+
+// LatestRevisions contiene la última revisión de un release y la última desplegada
+type LatestRevisions struct {
+	Latest Record
+	// Deployed es nil si ninguna revisión está en estado deployed
+	Deployed *Record
+}
+
+// SelectLatest agrupa los registros por release (namespace, nombre y driver de almacenamiento) y selecciona la última revisión y la última
+// desplegada de cada uno, sin decodificar ninguna. El resultado se ordena por namespace y nombre.
+func SelectLatest(records []Record) []LatestRevisions {
+	selected := make(map[string]*LatestRevisions)
+
+	for _, record := range records {
+		key := record.Namespace + "/" + record.Name + "/" + record.Storage
+
+		revisions, exists := selected[key]
+		if !exists {
+			revisions = &LatestRevisions{Latest: record}
+			selected[key] = revisions
+		} else if record.newerThan(revisions.Latest) {
+			revisions.Latest = record
+		}
+
+		if record.IsDeployed() && (revisions.Deployed == nil || record.newerThan(*revisions.Deployed)) {
+			deployed := record
+			revisions.Deployed = &deployed
+		}
+	}
+
+	result := make([]LatestRevisions, 0, len(selected))
+	for _, revisions := range selected {
+		result = append(result, *revisions)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i].Latest, result[j].Latest
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Storage < b.Storage
+	})
+
+	return result
+}
@@ -0,0 +1,192 @@
+package helm
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"encoding/json"
+	"slices"
+	"strconv"
+	"testing"
+	"time"
+
+	"helm.sh/helm/v3/pkg/release"
+	helmtime "helm.sh/helm/v3/pkg/time"
+)
+
+// encodeRelease codifica un release como lo guarda el driver secret de Helm 3
+func encodeRelease(t *testing.T, rel *release.Release) string {
+	t.Helper()
+
+	data, err := json.Marshal(rel)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+// labeled crea un registro a partir de las etiquetas del driver de almacenamiento
+func labeled(t *testing.T, key, namespace, name string, revision int, status string, modifiedAt string) Record {
+	t.Helper()
+
+	labels := map[string]string{
+		"name":    name,
+		"owner":   "helm",
+		"status":  status,
+		"version": strconv.Itoa(revision),
+	}
+	if modifiedAt != "" {
+		labels["modifiedAt"] = modifiedAt
+	}
+
+	record, err := NewRecord(key, namespace, labels, "", StorageSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return record
+}
+
+// unlabeled crea un registro sin etiquetas, como los que escriben versiones antiguas de Helm,
+// cuyo nombre y revisión solo se conocen decodificando el contenido
+func unlabeled(t *testing.T, key, namespace, name string, revision int, status release.Status, deployedAt int64) Record {
+	t.Helper()
+
+	data := encodeRelease(t, &release.Release{
+		Name:      name,
+		Namespace: namespace,
+		Version:   revision,
+		Info: &release.Info{
+			Status:       status,
+			LastDeployed: helmtime.Time{Time: time.Unix(deployedAt, 0)},
+		},
+	})
+
+	record, err := NewRecord(key, namespace, nil, data, StorageSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return record
+}
+
+func TestSelectLatest(t *testing.T) {
+	type selection struct {
+		latest, deployed string
+	}
+
+	tests := []struct {
+		name    string
+		records func(t *testing.T) []Record
+		want    []selection
+	}{
+		{
+			name: "equal revisions broken by modifiedAt",
+			records: func(t *testing.T) []Record {
+				return []Record{
+					labeled(t, "sh.helm.release.v1.app.v3.b", "default", "app", 3, "deployed", "100"),
+					labeled(t, "sh.helm.release.v1.app.v3.a", "default", "app", 3, "deployed", "200"),
+					labeled(t, "sh.helm.release.v1.app.v2", "default", "app", 2, "superseded", "300"),
+				}
+			},
+			want: []selection{{latest: "sh.helm.release.v1.app.v3.a", deployed: "sh.helm.release.v1.app.v3.a"}},
+		},
+		{
+			name: "equal revisions and modifiedAt broken by key",
+			records: func(t *testing.T) []Record {
+				return []Record{
+					labeled(t, "sh.helm.release.v1.app.v3.a", "default", "app", 3, "failed", "100"),
+					labeled(t, "sh.helm.release.v1.app.v3.b", "default", "app", 3, "failed", "100"),
+				}
+			},
+			want: []selection{{latest: "sh.helm.release.v1.app.v3.b"}},
+		},
+		{
+			name: "superseded deployed revision next to a newer failed one",
+			records: func(t *testing.T) []Record {
+				return []Record{
+					labeled(t, "sh.helm.release.v1.app.v1", "default", "app", 1, "superseded", "100"),
+					labeled(t, "sh.helm.release.v1.app.v2", "default", "app", 2, "deployed", "200"),
+					labeled(t, "sh.helm.release.v1.app.v3", "default", "app", 3, "failed", "300"),
+				}
+			},
+			want: []selection{{latest: "sh.helm.release.v1.app.v3", deployed: "sh.helm.release.v1.app.v2"}},
+		},
+		{
+			name: "revision compared numerically",
+			records: func(t *testing.T) []Record {
+				return []Record{
+					labeled(t, "sh.helm.release.v1.app.v10", "default", "app", 10, "deployed", ""),
+					labeled(t, "sh.helm.release.v1.app.v9", "default", "app", 9, "superseded", ""),
+				}
+			},
+			want: []selection{{latest: "sh.helm.release.v1.app.v10", deployed: "sh.helm.release.v1.app.v10"}},
+		},
+		{
+			name: "label-less records",
+			records: func(t *testing.T) []Record {
+				return []Record{
+					unlabeled(t, "sh.helm.release.v1.db.v1", "data", "db", 1, release.StatusDeployed, 100),
+					unlabeled(t, "sh.helm.release.v1.db.v2", "data", "db", 2, release.StatusPendingUpgrade, 200),
+					labeled(t, "sh.helm.release.v1.app.v1", "default", "app", 1, "deployed", "100"),
+					unlabeled(t, "sh.helm.release.v1.app.v2", "default", "app", 2, release.StatusDeployed, 50),
+				}
+			},
+			want: []selection{
+				{latest: "sh.helm.release.v1.db.v2", deployed: "sh.helm.release.v1.db.v1"},
+				{latest: "sh.helm.release.v1.app.v2", deployed: "sh.helm.release.v1.app.v2"},
+			},
+		},
+		{
+			name: "releases grouped by namespace and sorted",
+			records: func(t *testing.T) []Record {
+				return []Record{
+					labeled(t, "sh.helm.release.v1.app.v1.b", "b", "app", 1, "deployed", ""),
+					labeled(t, "sh.helm.release.v1.app.v2.a", "a", "app", 2, "deployed", ""),
+					labeled(t, "sh.helm.release.v1.api.v1.a", "a", "api", 1, "uninstalling", ""),
+				}
+			},
+			want: []selection{
+				{latest: "sh.helm.release.v1.api.v1.a"},
+				{latest: "sh.helm.release.v1.app.v2.a", deployed: "sh.helm.release.v1.app.v2.a"},
+				{latest: "sh.helm.release.v1.app.v1.b", deployed: "sh.helm.release.v1.app.v1.b"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			records := tt.records(t)
+
+			// La selección no depende del orden en que el API server retorna los objetos
+			reversed := slices.Clone(records)
+			slices.Reverse(reversed)
+
+			for _, input := range [][]Record{records, reversed} {
+				got := SelectLatest(input)
+				if len(got) != len(tt.want) {
+					t.Fatalf("SelectLatest() = %d releases, want %d", len(got), len(tt.want))
+				}
+				for i, want := range tt.want {
+					if got[i].Latest.Key != want.latest {
+						t.Errorf("release %d: latest = %s, want %s", i, got[i].Latest.Key, want.latest)
+					}
+					deployed := ""
+					if got[i].Deployed != nil {
+						deployed = got[i].Deployed.Key
+					}
+					if deployed != want.deployed {
+						t.Errorf("release %d: deployed = %q, want %q", i, deployed, want.deployed)
+					}
+				}
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"strings"
+	"time"
 
 	"helm.sh/helm/v3/pkg/release"
 	"k8s.io/apimachinery/pkg/util/yaml"
@@ -22,6 +23,30 @@ type Release struct {
 	Status       string
 	Revision     int
 	Sources      []string
+
+	// Última revisión en estado deployed (0 si no hay ninguna) y su versión de chart.
+	// Version, Status y Revision corresponden a la última revisión, que puede estar failed o pending.
+	DeployedRevision int
+	DeployedVersion  string
+
+	// Versión de la aplicación (appVersion del chart) de la última revisión y de la desplegada
+	AppVersion         string
+	DeployedAppVersion string
+
+	// Dependencias y manifiesto renderizado del chart desplegado (o de la última revisión si
+	// ninguna está desplegada)
+	Dependencies []Dependency
+	Manifest     string
+
+	// Values por defecto del chart desplegado y values suministrados por el usuario al release
+	ChartValues map[string]interface{}
+	Config      map[string]interface{}
+
+	// Momento desde el cual la última revisión está en su estado actual
+	StatusSince time.Time
+
+	// Driver de almacenamiento del release (secret, configmap o sql)
+	Storage string
 }
 
 // DecodeRelease decodifica un secret de Helm en un release
@@ -73,16 +98,30 @@ func ExtractReleaseInfo(rel *release.Release) *Release {
 		}
 	}
 
-	return &Release{
-		Name:      rel.Name,
-		Namespace: rel.Namespace,
-		ChartName: chartName,
-		ChartRepo: chartRepo,
-		Version:   rel.Chart.Metadata.Version,
-		Status:    string(rel.Info.Status),
-		Revision:  rel.Version,
-		Sources:   rel.Chart.Metadata.Sources,
+	info := &Release{
+		Name:         rel.Name,
+		Namespace:    rel.Namespace,
+		ChartName:    chartName,
+		ChartRepo:    chartRepo,
+		Version:      rel.Chart.Metadata.Version,
+		Status:       string(rel.Info.Status),
+		Revision:     rel.Version,
+		Sources:      rel.Chart.Metadata.Sources,
+		StatusSince:  rel.Info.LastDeployed.Time,
+		AppVersion:   rel.Chart.Metadata.AppVersion,
+		Dependencies: ExtractDependencies(rel.Chart),
+		Manifest:     rel.Manifest,
+		ChartValues:  rel.Chart.Values,
+		Config:       rel.Config,
+	}
+
+	if rel.Info.Status == release.StatusDeployed {
+		info.DeployedRevision = rel.Version
+		info.DeployedVersion = info.Version
+		info.DeployedAppVersion = info.AppVersion
 	}
+
+	return info
 }
 
 // extractRepoFromSources intenta extraer el nombre del repo desde los sources
@@ -0,0 +1,91 @@
+package helm
+
+import (
+	"fmt"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+	"helm.sh/helm/v3/pkg/releaseutil"
+)
+
+// Render renderiza localmente, sin acceder al cluster, el manifiesto que produciría actualizar el
+// release al chart indicado con los values del usuario guardados en el release. Como Helm, omite
+// los hooks y NOTES.txt y ordena los objetos según el orden de instalación. Las funciones que
+// consultan el cluster (lookup) retornan vacío. Las dependencias se procesan sobre una copia, de
+// modo que el chart recibido, compartido entre releases y clusters, no se modifica.
+func Render(c *chart.Chart, rel *Release, kubeVersion string) (string, error) {
+	c = copyChart(c)
+	if err := chartutil.ProcessDependenciesWithMerge(c, rel.Config); err != nil {
+		return "", fmt.Errorf("processing dependencies: %w", err)
+	}
+
+	caps := chartutil.DefaultCapabilities.Copy()
+	if kv, err := chartutil.ParseKubeVersion(kubeVersion); err == nil {
+		caps.KubeVersion = *kv
+	}
+
+	options := chartutil.ReleaseOptions{
+		Name:      rel.Name,
+		Namespace: rel.Namespace,
+		Revision:  rel.Revision + 1,
+		IsUpgrade: true,
+	}
+
+	// Los errores de values.schema.json se reportan aparte con ValidateValues
+	values, err := chartutil.ToRenderValuesWithSchemaValidation(c, rel.Config, options, caps, true)
+	if err != nil {
+		return "", fmt.Errorf("preparing values: %w", err)
+	}
+
+	files, err := engine.Render(c, values)
+	if err != nil {
+		return "", fmt.Errorf("rendering templates: %w", err)
+	}
+	for name := range files {
+		if strings.HasSuffix(name, "NOTES.txt") {
+			delete(files, name)
+		}
+	}
+
+	_, manifests, err := releaseutil.SortManifests(files, nil, releaseutil.InstallOrder)
+	if err != nil {
+		return "", fmt.Errorf("sorting manifests: %w", err)
+	}
+
+	// Mismo formato que el manifiesto que Helm guarda en el release
+	var b strings.Builder
+	for _, m := range manifests {
+		fmt.Fprintf(&b, "---\n# Source: %s\n%s\n", m.Name, m.Content)
+	}
+	return b.String(), nil
+}
+
+// copyChart copia un chart con sus subcharts y la metadata de sus dependencias, que es lo que
+// modifica el procesamiento de dependencias (descarta subcharts, aplica alias y marca Enabled).
+// Los values se reemplazan en lugar de modificarse, y templates y archivos solo se leen, así
+// que se comparten con el original.
+func copyChart(c *chart.Chart) *chart.Chart {
+	copied := *c
+	if c.Metadata != nil {
+		metadata := *c.Metadata
+		if c.Metadata.Dependencies != nil {
+			metadata.Dependencies = make([]*chart.Dependency, len(c.Metadata.Dependencies))
+			for i, dependency := range c.Metadata.Dependencies {
+				if dependency != nil {
+					d := *dependency
+					metadata.Dependencies[i] = &d
+				}
+			}
+		}
+		copied.Metadata = &metadata
+	}
+
+	dependencies := make([]*chart.Chart, 0, len(c.Dependencies()))
+	for _, dependency := range c.Dependencies() {
+		dependencies = append(dependencies, copyChart(dependency))
+	}
+	copied.SetDependencies(dependencies...)
+	return &copied
+}
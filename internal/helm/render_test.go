@@ -0,0 +1,79 @@
+package helm
+
+import (
+	"strings"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+// newRenderChart crea un chart con un subchart opcional referenciado con alias, como los que
+// DownloadChart comparte entre releases y clusters
+func newRenderChart() *chart.Chart {
+	postgresql := &chart.Chart{
+		Metadata:  &chart.Metadata{Name: "postgresql", APIVersion: "v2", Version: "12.0.0"},
+		Values:    map[string]interface{}{"port": 5432},
+		Templates: []*chart.File{{Name: "templates/service.yaml", Data: []byte("apiVersion: v1\nkind: Service\nmetadata:\n  name: {{ .Release.Name }}-db\nspec:\n  ports:\n    - port: {{ .Values.port }}\n")}},
+	}
+	app := &chart.Chart{
+		Metadata: &chart.Metadata{
+			Name:       "app",
+			APIVersion: "v2",
+			Version:    "2.0.0",
+			Dependencies: []*chart.Dependency{
+				{Name: "postgresql", Version: "12.x", Alias: "db", Condition: "db.enabled"},
+			},
+		},
+		Values:    map[string]interface{}{"db": map[string]interface{}{"enabled": false}},
+		Templates: []*chart.File{{Name: "templates/configmap.yaml", Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: {{ .Release.Name }}\n")}},
+	}
+	app.SetDependencies(postgresql)
+	return app
+}
+
+func TestRenderDoesNotModifyChart(t *testing.T) {
+	c := newRenderChart()
+
+	disabled := &Release{Name: "web", Namespace: "default", Revision: 1}
+	enabled := &Release{Name: "web", Namespace: "default", Revision: 1, Config: map[string]interface{}{
+		"db": map[string]interface{}{"enabled": true, "port": 6432},
+	}}
+
+	// El mismo chart se renderiza para varios releases: el subchart descartado en el primero
+	// debe seguir disponible para el segundo
+	for i, tt := range []struct {
+		rel    *Release
+		wantDB bool
+	}{
+		{disabled, false},
+		{enabled, true},
+		{disabled, false},
+	} {
+		manifest, err := Render(c, tt.rel, "v1.30.0")
+		if err != nil {
+			t.Fatalf("render %d: Render() unexpected error: %v", i, err)
+		}
+		if !strings.Contains(manifest, "kind: ConfigMap") {
+			t.Errorf("render %d: manifest without the chart templates:\n%s", i, manifest)
+		}
+		if got := strings.Contains(manifest, "name: web-db"); got != tt.wantDB {
+			t.Errorf("render %d: subchart rendered = %v, want %v:\n%s", i, got, tt.wantDB, manifest)
+		}
+		if tt.wantDB && !strings.Contains(manifest, "port: 6432") {
+			t.Errorf("render %d: subchart without the release values:\n%s", i, manifest)
+		}
+	}
+
+	if len(c.Dependencies()) != 1 || c.Dependencies()[0].Parent() != c {
+		t.Errorf("chart dependencies = %d, want the original subchart", len(c.Dependencies()))
+	}
+	if len(c.Metadata.Dependencies) != 1 {
+		t.Fatalf("chart dependency metadata = %d entries, want 1", len(c.Metadata.Dependencies))
+	}
+	if dependency := c.Metadata.Dependencies[0]; dependency.Name != "postgresql" || dependency.Enabled {
+		t.Errorf("chart dependency metadata modified: name = %s, enabled = %v", dependency.Name, dependency.Enabled)
+	}
+	if _, exists := c.Values["postgresql"]; exists {
+		t.Errorf("chart values modified: %v", c.Values)
+	}
+}
@@ -0,0 +1,118 @@
+package helm
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path"
+	"strconv"
+
+	// Driver PostgreSQL, el único soportado por el driver SQL de Helm
+	_ "github.com/lib/pq"
+	"sigs.k8s.io/yaml"
+)
+
+// sqlReleasesQuery lee las revisiones de la tabla que usa el driver SQL de Helm (HELM_DRIVER=sql)
+const sqlReleasesQuery = `SELECT key, name, namespace, version, status, modifiedAt, body FROM releases_v1 WHERE owner = 'helm'`
+
+// SQLConfig contiene las bases de datos del driver SQL de Helm de cada cluster
+type SQLConfig struct {
+	Sources []SQLSource `json:"sources"`
+}
+
+// SQLSource asocia los clusters que coinciden con Cluster (patrón glob, vacío = todos)
+// con la base de datos PostgreSQL donde Helm guarda sus releases
+type SQLSource struct {
+	Cluster string `json:"cluster"`
+	// DSN admite referencias a variables de entorno ($VAR) para no guardar credenciales en el archivo
+	DSN string `json:"dsn"`
+}
+
+// LoadSQLConfig lee y valida un archivo de configuración del driver SQL en formato YAML
+func LoadSQLConfig(filename string) (*SQLConfig, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("reading sql config file: %w", err)
+	}
+
+	var config SQLConfig
+	if err := yaml.UnmarshalStrict(data, &config); err != nil {
+		return nil, fmt.Errorf("parsing sql config file: %w", err)
+	}
+
+	for i := range config.Sources {
+		source := &config.Sources[i]
+		if source.DSN == "" {
+			return nil, fmt.Errorf("source %d: dsn is required", i)
+		}
+		if _, err := path.Match(source.Cluster, ""); err != nil {
+			return nil, fmt.Errorf("source %d: invalid cluster selector %q: %w", i, source.Cluster, err)
+		}
+		source.DSN = os.ExpandEnv(source.DSN)
+	}
+
+	return &config, nil
+}
+
+// For retorna la primera fuente SQL que coincide con el cluster indicado
+func (c *SQLConfig) For(cluster string) (*SQLSource, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	for i := range c.Sources {
+		source := &c.Sources[i]
+		if source.Cluster == "" {
+			return source, true
+		}
+		if matched, _ := path.Match(source.Cluster, cluster); matched {
+			return source, true
+		}
+	}
+
+	return nil, false
+}
+
+// ListRecords lee todas las revisiones guardadas en la base de datos de la fuente
+func (s *SQLSource) ListRecords(ctx context.Context) ([]Record, error) {
+	db, err := sql.Open("postgres", s.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	rows, err := db.QueryContext(ctx, sqlReleasesQuery)
+	if err != nil {
+		return nil, fmt.Errorf("querying releases: %w", err)
+	}
+	defer rows.Close()
+
+	var records []Record
+	for rows.Next() {
+		var (
+			key, name, namespace, status, body string
+			revision, modifiedAt               int64
+		)
+		if err := rows.Scan(&key, &name, &namespace, &revision, &status, &modifiedAt, &body); err != nil {
+			return nil, fmt.Errorf("scanning release: %w", err)
+		}
+
+		// Las columnas replican las etiquetas que Helm usa en Secrets y ConfigMaps
+		record, err := NewRecord(key, namespace, map[string]string{
+			"name":       name,
+			"version":    strconv.FormatInt(revision, 10),
+			"status":     status,
+			"modifiedAt": strconv.FormatInt(modifiedAt, 10),
+		}, body, StorageSQL)
+		if err != nil {
+			continue
+		}
+		records = append(records, record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading releases: %w", err)
+	}
+
+	return records, nil
+}
@@ -0,0 +1,313 @@
+package helm
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"strings"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	helmtime "helm.sh/helm/v3/pkg/time"
+	"sigs.k8s.io/yaml"
+)
+
+// TillerOwnerSelector selecciona los ConfigMaps en los que Tiller (Helm 2) guarda sus releases
+const TillerOwnerSelector = "OWNER=TILLER"
+
+// tillerStatuses traduce los códigos de estado de Helm 2 (hapi.release.Status.Code) a los de Helm 3
+var tillerStatuses = map[uint64]release.Status{
+	0: release.StatusUnknown,
+	1: release.StatusDeployed,
+	2: release.StatusUninstalled,
+	3: release.StatusSuperseded,
+	4: release.StatusFailed,
+	5: release.StatusUninstalling,
+	6: release.StatusPendingInstall,
+	7: release.StatusPendingUpgrade,
+	8: release.StatusPendingRollback,
+}
+
+// NewTillerRecord crea el registro de una revisión de Helm 2 a partir de las etiquetas de su
+// ConfigMap (NAME, VERSION, STATUS, MODIFIED_AT), con los estados traducidos a los de Helm 3
+func NewTillerRecord(key, namespace string, labels map[string]string, data string) (Record, error) {
+	status := strings.ToLower(strings.ReplaceAll(labels["STATUS"], "_", "-"))
+	if status == "deleted" {
+		status = string(release.StatusUninstalled)
+	}
+
+	return NewRecord(key, namespace, map[string]string{
+		"name":       labels["NAME"],
+		"version":    labels["VERSION"],
+		"status":     status,
+		"modifiedAt": labels["MODIFIED_AT"],
+	}, data, StorageTiller)
+}
+
+// DecodeTillerRelease decodifica un release de Helm 2 (base64 + gzip + protobuf hapi.release.Release)
+// al modelo de releases de Helm 3
+func DecodeTillerRelease(encoded string) (*release.Release, error) {
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("base64 decode: %w", err)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("gzip decompress: %w", err)
+	}
+	defer gz.Close()
+
+	raw, err := io.ReadAll(gz)
+	if err != nil {
+		return nil, fmt.Errorf("copying data: %w", err)
+	}
+
+	rel := &release.Release{Info: &release.Info{Status: release.StatusUnknown}}
+	err = walkFields(raw, func(num protowire.Number, value []byte, n uint64) error {
+		switch num {
+		case 1:
+			rel.Name = string(value)
+		case 2:
+			return decodeTillerInfo(value, rel.Info)
+		case 3:
+			c, err := decodeTillerChart(value)
+			if err != nil {
+				return fmt.Errorf("chart: %w", err)
+			}
+			rel.Chart = c
+		case 4:
+			config, err := decodeTillerConfig(value)
+			if err != nil {
+				return fmt.Errorf("config: %w", err)
+			}
+			rel.Config = config
+		case 5:
+			rel.Manifest = string(value)
+		case 7:
+			rel.Version = int(n)
+		case 8:
+			rel.Namespace = string(value)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("protobuf decode: %w", err)
+	}
+
+	if rel.Chart == nil {
+		rel.Chart = &chart.Chart{Metadata: &chart.Metadata{}}
+	}
+	return rel, nil
+}
+
+// decodeTillerInfo decodifica hapi.release.Info
+func decodeTillerInfo(data []byte, info *release.Info) error {
+	return walkFields(data, func(num protowire.Number, value []byte, _ uint64) error {
+		switch num {
+		case 1:
+			// hapi.release.Status: solo interesa el código
+			return walkFields(value, func(num protowire.Number, _ []byte, n uint64) error {
+				if num == 1 {
+					if status, ok := tillerStatuses[n]; ok {
+						info.Status = status
+					}
+				}
+				return nil
+			})
+		case 2:
+			t, err := decodeTimestamp(value)
+			info.FirstDeployed = t
+			return err
+		case 3:
+			t, err := decodeTimestamp(value)
+			info.LastDeployed = t
+			return err
+		case 4:
+			t, err := decodeTimestamp(value)
+			info.Deleted = t
+			return err
+		case 5:
+			info.Description = string(value)
+		}
+		return nil
+	})
+}
+
+// decodeTillerChart decodifica hapi.chart.Chart junto con sus dependencias
+func decodeTillerChart(data []byte) (*chart.Chart, error) {
+	c := &chart.Chart{Metadata: &chart.Metadata{}}
+	var dependencies []*chart.Chart
+
+	err := walkFields(data, func(num protowire.Number, value []byte, _ uint64) error {
+		switch num {
+		case 1:
+			return decodeTillerMetadata(value, c.Metadata)
+		case 2:
+			file, err := decodeTillerFile(value)
+			c.Templates = append(c.Templates, file)
+			return err
+		case 3:
+			dependency, err := decodeTillerChart(value)
+			if err != nil {
+				return err
+			}
+			dependencies = append(dependencies, dependency)
+		case 4:
+			values, err := decodeTillerConfig(value)
+			c.Values = values
+			return err
+		case 5:
+			file, err := decodeTillerFile(value)
+			c.Files = append(c.Files, file)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c.SetDependencies(dependencies...)
+	return c, nil
+}
+
+// decodeTillerMetadata decodifica hapi.chart.Metadata
+func decodeTillerMetadata(data []byte, metadata *chart.Metadata) error {
+	return walkFields(data, func(num protowire.Number, value []byte, n uint64) error {
+		switch num {
+		case 1:
+			metadata.Name = string(value)
+		case 2:
+			metadata.Home = string(value)
+		case 3:
+			metadata.Sources = append(metadata.Sources, string(value))
+		case 4:
+			metadata.Version = string(value)
+		case 5:
+			metadata.Description = string(value)
+		case 10:
+			metadata.APIVersion = string(value)
+		case 13:
+			metadata.AppVersion = string(value)
+		case 14:
+			metadata.Deprecated = n != 0
+		case 16:
+			key, val, err := decodeMapEntry(value)
+			if err != nil {
+				return err
+			}
+			if metadata.Annotations == nil {
+				metadata.Annotations = make(map[string]string)
+			}
+			metadata.Annotations[key] = val
+		case 17:
+			metadata.KubeVersion = string(value)
+		}
+		return nil
+	})
+}
+
+// decodeTillerFile decodifica hapi.chart.Template y google.protobuf.Any, ambos con
+// el nombre en el campo 1 y el contenido en el campo 2
+func decodeTillerFile(data []byte) (*chart.File, error) {
+	file := &chart.File{}
+	err := walkFields(data, func(num protowire.Number, value []byte, _ uint64) error {
+		switch num {
+		case 1:
+			file.Name = string(value)
+		case 2:
+			file.Data = value
+		}
+		return nil
+	})
+	return file, err
+}
+
+// decodeTillerConfig decodifica hapi.chart.Config, cuyos valores están en YAML en el campo raw
+func decodeTillerConfig(data []byte) (map[string]interface{}, error) {
+	var raw string
+	err := walkFields(data, func(num protowire.Number, value []byte, _ uint64) error {
+		if num == 1 {
+			raw = string(value)
+		}
+		return nil
+	})
+	if err != nil || strings.TrimSpace(raw) == "" {
+		return nil, err
+	}
+
+	values := make(map[string]interface{})
+	if err := yaml.Unmarshal([]byte(raw), &values); err != nil {
+		return nil, fmt.Errorf("parsing values: %w", err)
+	}
+	return values, nil
+}
+
+// decodeTimestamp decodifica google.protobuf.Timestamp
+func decodeTimestamp(data []byte) (helmtime.Time, error) {
+	var seconds, nanos uint64
+	err := walkFields(data, func(num protowire.Number, _ []byte, n uint64) error {
+		switch num {
+		case 1:
+			seconds = n
+		case 2:
+			nanos = n
+		}
+		return nil
+	})
+	return helmtime.Unix(int64(seconds), int64(int32(nanos))), err
+}
+
+// decodeMapEntry decodifica una entrada de un map<string, string> de protobuf
+func decodeMapEntry(data []byte) (string, string, error) {
+	var key, value string
+	err := walkFields(data, func(num protowire.Number, v []byte, _ uint64) error {
+		switch num {
+		case 1:
+			key = string(v)
+		case 2:
+			value = string(v)
+		}
+		return nil
+	})
+	return key, value, err
+}
+
+// walkFields recorre los campos de un mensaje protobuf. Para los campos length-delimited
+// entrega su contenido en value y para los varint su valor en n; el resto de tipos se omite.
+func walkFields(data []byte, fn func(num protowire.Number, value []byte, n uint64) error) error {
+	for len(data) > 0 {
+		num, typ, length := protowire.ConsumeTag(data)
+		if length < 0 {
+			return protowire.ParseError(length)
+		}
+		data = data[length:]
+
+		var value []byte
+		var n uint64
+		switch typ {
+		case protowire.BytesType:
+			value, length = protowire.ConsumeBytes(data)
+		case protowire.VarintType:
+			n, length = protowire.ConsumeVarint(data)
+		default:
+			length = protowire.ConsumeFieldValue(num, typ, data)
+		}
+		if length < 0 {
+			return protowire.ParseError(length)
+		}
+		data = data[length:]
+
+		if typ != protowire.BytesType && typ != protowire.VarintType {
+			continue
+		}
+		if err := fn(num, value, n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
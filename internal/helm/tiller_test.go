@@ -0,0 +1,335 @@
+package helm
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"reflect"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/release"
+	helmtime "helm.sh/helm/v3/pkg/time"
+)
+
+// message construye un mensaje protobuf concatenando sus campos
+func message(fields ...[]byte) []byte {
+	return bytes.Join(fields, nil)
+}
+
+// bytesField codifica un campo length-delimited (string, bytes o mensaje anidado)
+func bytesField(num protowire.Number, value []byte) []byte {
+	b := protowire.AppendTag(nil, num, protowire.BytesType)
+	return protowire.AppendBytes(b, value)
+}
+
+// stringField codifica un campo string
+func stringField(num protowire.Number, value string) []byte {
+	return bytesField(num, []byte(value))
+}
+
+// varintField codifica un campo varint (enteros, enums y bool)
+func varintField(num protowire.Number, value uint64) []byte {
+	b := protowire.AppendTag(nil, num, protowire.VarintType)
+	return protowire.AppendVarint(b, value)
+}
+
+// timestamp codifica un google.protobuf.Timestamp
+func timestamp(seconds, nanos int64) []byte {
+	return message(varintField(1, uint64(seconds)), varintField(2, uint64(nanos)))
+}
+
+// encodeTiller codifica un hapi.release.Release como lo guarda Tiller en sus ConfigMaps
+func encodeTiller(t *testing.T, raw []byte) string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if _, err := gz.Write(raw); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func TestDecodeTillerRelease(t *testing.T) {
+	tests := []struct {
+		name string
+		raw  []byte
+		want *release.Release
+	}{
+		{
+			name: "empty release",
+			raw:  nil,
+			want: &release.Release{
+				Info:  &release.Info{Status: release.StatusUnknown},
+				Chart: &chart.Chart{Metadata: &chart.Metadata{}},
+			},
+		},
+		{
+			name: "full release",
+			raw: message(
+				stringField(1, "app"),
+				bytesField(2, message(
+					bytesField(1, message(varintField(1, 1))),
+					bytesField(2, timestamp(1600000000, 0)),
+					bytesField(3, timestamp(1700000000, 500)),
+					stringField(5, "Upgrade complete"),
+				)),
+				bytesField(3, message(
+					bytesField(1, message(
+						stringField(1, "app"),
+						stringField(2, "https://example.com"),
+						stringField(3, "https://github.com/example/app"),
+						stringField(4, "1.2.3"),
+						stringField(5, "An app"),
+						stringField(10, "v1"),
+						stringField(13, "2.0.0"),
+						varintField(14, 1),
+						bytesField(16, message(stringField(1, "category"), stringField(2, "web"))),
+						stringField(17, ">= 1.10.0"),
+					)),
+					bytesField(2, message(stringField(1, "templates/deployment.yaml"), stringField(2, "kind: Deployment"))),
+					bytesField(3, message(
+						bytesField(1, message(stringField(1, "redis"), stringField(4, "3.0.0"))),
+						bytesField(3, message(
+							bytesField(1, message(stringField(1, "sentinel"), stringField(4, "0.1.0"))),
+						)),
+					)),
+					bytesField(4, message(stringField(1, "replicas: 1\n"))),
+					bytesField(5, message(stringField(1, "README.md"), stringField(2, "# app"))),
+				)),
+				bytesField(4, message(stringField(1, "image:\n  tag: v2\nreplicas: 3\n"))),
+				stringField(5, "---\nkind: Deployment\n"),
+				varintField(7, 4),
+				stringField(8, "web"),
+			),
+			want: &release.Release{
+				Name:      "app",
+				Namespace: "web",
+				Version:   4,
+				Manifest:  "---\nkind: Deployment\n",
+				Info: &release.Info{
+					Status:        release.StatusDeployed,
+					FirstDeployed: helmtime.Unix(1600000000, 0),
+					LastDeployed:  helmtime.Unix(1700000000, 500),
+					Description:   "Upgrade complete",
+				},
+				Config: map[string]interface{}{
+					"image":    map[string]interface{}{"tag": "v2"},
+					"replicas": float64(3),
+				},
+				Chart: &chart.Chart{
+					Metadata: &chart.Metadata{
+						Name:        "app",
+						Home:        "https://example.com",
+						Sources:     []string{"https://github.com/example/app"},
+						Version:     "1.2.3",
+						Description: "An app",
+						APIVersion:  "v1",
+						AppVersion:  "2.0.0",
+						Deprecated:  true,
+						Annotations: map[string]string{"category": "web"},
+						KubeVersion: ">= 1.10.0",
+					},
+					Templates: []*chart.File{{Name: "templates/deployment.yaml", Data: []byte("kind: Deployment")}},
+					Values:    map[string]interface{}{"replicas": float64(1)},
+					Files:     []*chart.File{{Name: "README.md", Data: []byte("# app")}},
+				},
+			},
+		},
+		{
+			name: "unknown fields are skipped",
+			raw: message(
+				stringField(1, "app"),
+				// hapi.release.Hook y campos que no existen en ninguna versión de hapi
+				bytesField(6, message(stringField(1, "pre-install"))),
+				protowire.AppendFixed32(protowire.AppendTag(nil, 90, protowire.Fixed32Type), 7),
+				protowire.AppendFixed64(protowire.AppendTag(nil, 91, protowire.Fixed64Type), 7),
+				varintField(92, 1),
+				bytesField(2, message(
+					bytesField(1, message(varintField(1, 3), stringField(2, "notes"))),
+					varintField(99, 1),
+				)),
+				varintField(7, 2),
+			),
+			want: &release.Release{
+				Name:    "app",
+				Version: 2,
+				Info:    &release.Info{Status: release.StatusSuperseded},
+				Chart:   &chart.Chart{Metadata: &chart.Metadata{}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeTillerRelease(encodeTiller(t, tt.raw))
+			if err != nil {
+				t.Fatalf("DecodeTillerRelease() unexpected error: %v", err)
+			}
+
+			if got.Name != tt.want.Name || got.Namespace != tt.want.Namespace || got.Version != tt.want.Version || got.Manifest != tt.want.Manifest {
+				t.Errorf("release = %s/%s v%d %q, want %s/%s v%d %q",
+					got.Namespace, got.Name, got.Version, got.Manifest,
+					tt.want.Namespace, tt.want.Name, tt.want.Version, tt.want.Manifest)
+			}
+			if !reflect.DeepEqual(got.Info, tt.want.Info) {
+				t.Errorf("info = %+v, want %+v", got.Info, tt.want.Info)
+			}
+			if !reflect.DeepEqual(got.Config, tt.want.Config) {
+				t.Errorf("config = %v, want %v", got.Config, tt.want.Config)
+			}
+			if !reflect.DeepEqual(got.Chart.Metadata, tt.want.Chart.Metadata) {
+				t.Errorf("metadata = %+v, want %+v", got.Chart.Metadata, tt.want.Chart.Metadata)
+			}
+			if !reflect.DeepEqual(got.Chart.Values, tt.want.Chart.Values) {
+				t.Errorf("chart values = %v, want %v", got.Chart.Values, tt.want.Chart.Values)
+			}
+			if !reflect.DeepEqual(got.Chart.Templates, tt.want.Chart.Templates) {
+				t.Errorf("templates = %v, want %v", got.Chart.Templates, tt.want.Chart.Templates)
+			}
+			if !reflect.DeepEqual(got.Chart.Files, tt.want.Chart.Files) {
+				t.Errorf("files = %v, want %v", got.Chart.Files, tt.want.Chart.Files)
+			}
+		})
+	}
+}
+
+func TestDecodeTillerReleaseDependencies(t *testing.T) {
+	raw := message(bytesField(3, message(
+		bytesField(1, message(stringField(1, "app"))),
+		bytesField(3, message(
+			bytesField(1, message(stringField(1, "redis"), stringField(4, "3.0.0"))),
+			bytesField(3, message(
+				bytesField(1, message(stringField(1, "sentinel"), stringField(4, "0.1.0"))),
+			)),
+		)),
+		bytesField(3, message(
+			bytesField(1, message(stringField(1, "postgresql"), stringField(4, "10.0.0"))),
+		)),
+	)))
+
+	rel, err := DecodeTillerRelease(encodeTiller(t, raw))
+	if err != nil {
+		t.Fatalf("DecodeTillerRelease() unexpected error: %v", err)
+	}
+
+	// Las dependencias se reconstruyen como subcharts con su padre asignado
+	var names []string
+	var walk func(c *chart.Chart)
+	walk = func(c *chart.Chart) {
+		names = append(names, c.ChartFullPath()+"@"+c.Metadata.Version)
+		for _, dependency := range c.Dependencies() {
+			if dependency.Parent() != c {
+				t.Errorf("%s: parent not set", dependency.Name())
+			}
+			walk(dependency)
+		}
+	}
+	walk(rel.Chart)
+
+	want := []string{"app@", "app/charts/redis@3.0.0", "app/charts/redis/charts/sentinel@0.1.0", "app/charts/postgresql@10.0.0"}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("charts = %v, want %v", names, want)
+	}
+}
+
+func TestDecodeTillerReleaseStatus(t *testing.T) {
+	tests := []struct {
+		code uint64
+		want release.Status
+	}{
+		{0, release.StatusUnknown},
+		{1, release.StatusDeployed},
+		{2, release.StatusUninstalled},
+		{3, release.StatusSuperseded},
+		{4, release.StatusFailed},
+		{5, release.StatusUninstalling},
+		{6, release.StatusPendingInstall},
+		{7, release.StatusPendingUpgrade},
+		{8, release.StatusPendingRollback},
+		// Un código que no existe en Helm 2 se mantiene como desconocido
+		{42, release.StatusUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.want), func(t *testing.T) {
+			raw := message(bytesField(2, message(bytesField(1, message(varintField(1, tt.code))))))
+			rel, err := DecodeTillerRelease(encodeTiller(t, raw))
+			if err != nil {
+				t.Fatalf("DecodeTillerRelease() unexpected error: %v", err)
+			}
+			if rel.Info.Status != tt.want {
+				t.Errorf("status code %d = %s, want %s", tt.code, rel.Info.Status, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeTillerReleaseErrors(t *testing.T) {
+	full := message(
+		stringField(1, "app"),
+		bytesField(2, message(bytesField(2, timestamp(1600000000, 0)))),
+		varintField(7, 1),
+	)
+
+	tests := []struct {
+		name    string
+		encoded func(t *testing.T) string
+		wantErr string
+	}{
+		{
+			name:    "invalid base64",
+			encoded: func(t *testing.T) string { return "not base64!" },
+			wantErr: "base64 decode",
+		},
+		{
+			name:    "not gzip",
+			encoded: func(t *testing.T) string { return base64.StdEncoding.EncodeToString([]byte("plain")) },
+			wantErr: "gzip decompress",
+		},
+		{
+			name:    "truncated message",
+			encoded: func(t *testing.T) string { return encodeTiller(t, full[:len(full)-1]) },
+			wantErr: "protobuf decode",
+		},
+		{
+			// El mensaje externo está completo pero el Info que contiene no
+			name: "truncated nested message",
+			encoded: func(t *testing.T) string {
+				info := message(bytesField(2, timestamp(1600000000, 0)))
+				return encodeTiller(t, message(stringField(1, "app"), bytesField(2, info[:len(info)-2])))
+			},
+			wantErr: "protobuf decode",
+		},
+		{
+			name:    "truncated tag",
+			encoded: func(t *testing.T) string { return encodeTiller(t, []byte{0x80}) },
+			wantErr: "protobuf decode",
+		},
+		{
+			name: "invalid config",
+			encoded: func(t *testing.T) string {
+				return encodeTiller(t, message(bytesField(4, message(stringField(1, "replicas: [")))))
+			},
+			wantErr: "config: parsing values",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DecodeTillerRelease(tt.encoded(t))
+			if err == nil {
+				t.Fatalf("DecodeTillerRelease() = nil error, want %q", tt.wantErr)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("DecodeTillerRelease() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package helm
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+)
+
+// ValuesDrift describe los cambios en los values por defecto entre el chart instalado y una
+// versión más nueva, y su impacto en los values suministrados por el usuario al release.
+// Las claves se expresan como rutas separadas por puntos (ej. controller.service.type).
+type ValuesDrift struct {
+	Added   []string
+	Removed []string
+	// Changed son las claves presentes en ambas versiones con otro valor por defecto
+	Changed []string
+	// Renamed asocia cada clave eliminada con la clave agregada del mismo nombre final, si es única
+	Renamed map[string]string
+
+	// Overridden son las claves eliminadas (o renombradas) que los values del release todavía definen
+	Overridden []string
+	// SchemaErrors son los errores de validar los values del release contra el
+	// values.schema.json de la nueva versión
+	SchemaErrors []string
+}
+
+// Breaking indica si los values del release no son compatibles con la nueva versión
+func (d ValuesDrift) Breaking() bool {
+	return len(d.Overridden) > 0 || len(d.SchemaErrors) > 0
+}
+
+// CompareValues compara los values por defecto del chart instalado con los de la nueva versión
+// y busca las claves eliminadas que los values del release (config) siguen definiendo
+func CompareValues(installed, latest, config map[string]interface{}) ValuesDrift {
+	installedNodes := flattenValues(installed)
+	latestNodes := flattenValues(latest)
+
+	drift := ValuesDrift{
+		Added:   topmostMissing(latestNodes, installedNodes),
+		Removed: topmostMissing(installedNodes, latestNodes),
+		Renamed: make(map[string]string),
+	}
+
+	for key, node := range installedNodes {
+		other, exists := latestNodes[key]
+		if !exists || (!isLeaf(node.value) && !isLeaf(other.value)) {
+			continue
+		}
+		if !reflect.DeepEqual(node.value, other.value) {
+			drift.Changed = append(drift.Changed, key)
+		}
+	}
+	sort.Strings(drift.Changed)
+
+	// Un renombre se detecta como una clave eliminada y otra nueva con el mismo nombre final,
+	// que puede estar dentro de una tabla agregada (ej. image → controller.image)
+	for _, removed := range drift.Removed {
+		var candidates []string
+		for key, node := range latestNodes {
+			if lastSegment(key) == lastSegment(removed) && !covered(installedNodes, node.path) {
+				candidates = append(candidates, key)
+			}
+		}
+		if len(candidates) == 1 {
+			drift.Renamed[removed] = candidates[0]
+		}
+	}
+
+	// Solo importan las claves que el chart instalado conocía: el resto son values libres
+	configNodes := flattenValues(config)
+	for _, key := range topmostMissing(configNodes, latestNodes) {
+		if covered(installedNodes, configNodes[key].path) {
+			drift.Overridden = append(drift.Overridden, key)
+		}
+	}
+
+	return drift
+}
+
+// ValidateValues valida los values del release, combinados con los por defecto del chart,
+// contra los values.schema.json del chart y de sus subcharts
+func ValidateValues(c *chart.Chart, config map[string]interface{}) []string {
+	values, err := chartutil.CoalesceValues(c, config)
+	if err != nil {
+		return []string{err.Error()}
+	}
+
+	err = chartutil.ValidateAgainstSchema(c, values)
+	if err == nil {
+		return nil
+	}
+
+	// El error lista cada chart seguido de sus violaciones, una por línea con el prefijo "- "
+	var errs []string
+	scope := c.Name()
+	for _, line := range strings.Split(err.Error(), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			continue
+		case !strings.HasPrefix(line, "- ") && strings.HasSuffix(line, ":"):
+			scope = strings.TrimSuffix(line, ":")
+			continue
+		}
+
+		message := strings.TrimPrefix(line, "- ")
+		if scope != c.Name() {
+			message = scope + ": " + message
+		}
+		errs = append(errs, message)
+	}
+	return errs
+}
+
+// valueNode es un nodo (tabla o valor) dentro de un árbol de values
+type valueNode struct {
+	path  []string
+	value interface{}
+}
+
+// flattenValues indexa todos los nodos de un árbol de values por su ruta separada por puntos
+func flattenValues(values map[string]interface{}) map[string]valueNode {
+	nodes := make(map[string]valueNode)
+
+	var walk func(path []string, table map[string]interface{})
+	walk = func(path []string, table map[string]interface{}) {
+		for key, value := range table {
+			childPath := append(append([]string{}, path...), key)
+			nodes[strings.Join(childPath, ".")] = valueNode{path: childPath, value: value}
+			if child, ok := value.(map[string]interface{}); ok {
+				walk(childPath, child)
+			}
+		}
+	}
+	walk(nil, values)
+
+	return nodes
+}
+
+// topmostMissing retorna, ordenadas, las claves de nodes que no existen en other. Si falta una
+// tabla completa solo se reporta la tabla y no cada una de sus claves.
+func topmostMissing(nodes, other map[string]valueNode) []string {
+	var missing []string
+	for key, node := range nodes {
+		if covered(other, node.path) {
+			continue
+		}
+		if len(node.path) > 1 && !covered(other, node.path[:len(node.path)-1]) {
+			continue
+		}
+		missing = append(missing, key)
+	}
+	sort.Strings(missing)
+	return missing
+}
+
+// covered indica si la ruta existe en el árbol, o si alguno de sus ancestros es un valor o una
+// tabla vacía (ej. podAnnotations: {}), en cuyo caso el chart acepta claves libres debajo
+func covered(nodes map[string]valueNode, path []string) bool {
+	if _, exists := nodes[strings.Join(path, ".")]; exists {
+		return true
+	}
+	for i := len(path) - 1; i > 0; i-- {
+		if ancestor, exists := nodes[strings.Join(path[:i], ".")]; exists {
+			return isLeaf(ancestor.value)
+		}
+	}
+	return false
+}
+
+// isLeaf indica si el valor no es una tabla con claves
+func isLeaf(value interface{}) bool {
+	table, ok := value.(map[string]interface{})
+	return !ok || len(table) == 0
+}
+
+// lastSegment retorna el último componente de una ruta separada por puntos
+func lastSegment(key string) string {
+	return key[strings.LastIndex(key, ".")+1:]
+}
@@ -0,0 +1,164 @@
+package helm
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"helm.sh/helm/v3/pkg/chart"
+)
+
+func TestCompareValues(t *testing.T) {
+	tests := []struct {
+		name      string
+		installed map[string]interface{}
+		latest    map[string]interface{}
+		config    map[string]interface{}
+		want      ValuesDrift
+	}{
+		{
+			name: "added, removed and changed nested keys",
+			installed: map[string]interface{}{
+				"controller": map[string]interface{}{
+					"replicas": 1,
+					"service":  map[string]interface{}{"type": "ClusterIP", "port": 80},
+					"legacy":   map[string]interface{}{"enabled": true},
+				},
+				"image": map[string]interface{}{"tag": "1.0"},
+			},
+			latest: map[string]interface{}{
+				"controller": map[string]interface{}{
+					"replicas": 1,
+					"service":  map[string]interface{}{"type": "LoadBalancer", "port": 80, "annotations": map[string]interface{}{}},
+					"metrics":  map[string]interface{}{"enabled": false},
+				},
+				"image": map[string]interface{}{"tag": "2.0"},
+			},
+			want: ValuesDrift{
+				// De una tabla agregada o eliminada solo se reporta la tabla
+				Added:   []string{"controller.metrics", "controller.service.annotations"},
+				Removed: []string{"controller.legacy"},
+				Changed: []string{"controller.service.type", "image.tag"},
+				Renamed: map[string]string{},
+			},
+		},
+		{
+			name: "table replaced by a value",
+			installed: map[string]interface{}{
+				"resources": map[string]interface{}{"limits": map[string]interface{}{"cpu": "100m"}},
+			},
+			latest: map[string]interface{}{
+				"resources": map[string]interface{}{"limits": "none"},
+			},
+			// Bajo un valor el chart acepta cualquier clave, así que cpu no se reporta como eliminada
+			want: ValuesDrift{
+				Changed: []string{"resources.limits"},
+				Renamed: map[string]string{},
+			},
+		},
+		{
+			name:      "renamed key still set by the release",
+			installed: map[string]interface{}{"image": map[string]interface{}{"repository": "nginx"}},
+			latest: map[string]interface{}{
+				"controller": map[string]interface{}{"image": map[string]interface{}{"repository": "nginx"}},
+			},
+			config: map[string]interface{}{"image": map[string]interface{}{"repository": "registry.example.com/nginx"}},
+			want: ValuesDrift{
+				Added:      []string{"controller"},
+				Removed:    []string{"image"},
+				Renamed:    map[string]string{"image": "controller.image"},
+				Overridden: []string{"image"},
+			},
+		},
+		{
+			name:      "removed key not set by the release",
+			installed: map[string]interface{}{"legacy": true, "replicas": 1},
+			latest:    map[string]interface{}{"replicas": 1},
+			config:    map[string]interface{}{"replicas": 3},
+			want: ValuesDrift{
+				Removed: []string{"legacy"},
+				Renamed: map[string]string{},
+			},
+		},
+		{
+			// Las claves bajo tablas vacías y las que el chart nunca conoció son values libres
+			name:      "free-form values are ignored",
+			installed: map[string]interface{}{"podAnnotations": map[string]interface{}{}},
+			latest:    map[string]interface{}{"podAnnotations": map[string]interface{}{}},
+			config: map[string]interface{}{
+				"podAnnotations": map[string]interface{}{"example.com/team": "web"},
+				"extra":          map[string]interface{}{"enabled": true},
+			},
+			want: ValuesDrift{Renamed: map[string]string{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := CompareValues(tt.installed, tt.latest, tt.config)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CompareValues() = %+v, want %+v", got, tt.want)
+			}
+			if got.Breaking() != (len(tt.want.Overridden) > 0) {
+				t.Errorf("Breaking() = %v, want %v", got.Breaking(), len(tt.want.Overridden) > 0)
+			}
+		})
+	}
+}
+
+func TestValidateValues(t *testing.T) {
+	newChart := func() *chart.Chart {
+		db := &chart.Chart{
+			Metadata: &chart.Metadata{Name: "db", APIVersion: "v2", Version: "1.0.0"},
+			Values:   map[string]interface{}{"port": 5432},
+			Schema:   []byte(`{"type":"object","properties":{"port":{"type":"integer"}}}`),
+		}
+		c := &chart.Chart{
+			Metadata: &chart.Metadata{Name: "app", APIVersion: "v2", Version: "2.0.0"},
+			Values:   map[string]interface{}{"replicas": 1},
+			Schema:   []byte(`{"type":"object","properties":{"replicas":{"type":"integer","minimum":1}},"required":["replicas"]}`),
+		}
+		c.SetDependencies(db)
+		return c
+	}
+
+	tests := []struct {
+		name   string
+		config map[string]interface{}
+		// want son fragmentos de cada error esperado, en orden
+		want []string
+	}{
+		{
+			name:   "valid values",
+			config: map[string]interface{}{"replicas": 3, "db": map[string]interface{}{"port": 5433}},
+		},
+		{
+			name:   "defaults fill missing values",
+			config: nil,
+		},
+		{
+			name:   "violation in the chart",
+			config: map[string]interface{}{"replicas": 0},
+			want:   []string{"/replicas"},
+		},
+		{
+			name:   "violations in the chart and a subchart",
+			config: map[string]interface{}{"replicas": 0, "db": map[string]interface{}{"port": "5432"}},
+			want:   []string{"/replicas", "db: at '/port'"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ValidateValues(newChart(), tt.config)
+			if len(got) != len(tt.want) {
+				t.Fatalf("ValidateValues() = %q, want %d errors", got, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if !strings.Contains(got[i], want) {
+					t.Errorf("ValidateValues() error %d = %q, want it to contain %q", i, got[i], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package kubeapi
+
+import (
+	"fmt"
+
+	"github.com/start-codex/rke-update-checker/internal/manifest"
+	"github.com/start-codex/rke-update-checker/internal/version"
+)
+
+// Status clasifica un objeto con un apiVersion deprecado respecto de la versión objetivo
+type Status string
+
+const (
+	// StatusDeprecated indica que el apiVersion sigue disponible en la versión objetivo pero está deprecado
+	StatusDeprecated Status = "deprecated"
+	// StatusRemoved indica que el apiVersion ya no existe en la versión objetivo y bloquea la actualización
+	StatusRemoved Status = "removed"
+)
+
+// Finding representa un objeto del manifiesto de un release que usa un apiVersion deprecado
+type Finding struct {
+	Object manifest.Object
+	Deprecation
+	Status Status
+}
+
+// Blocking indica si el objeto impide actualizar a la versión objetivo
+func (f Finding) Blocking() bool {
+	return f.Status == StatusRemoved
+}
+
+// Check compara los apiVersion de los objetos con la tabla de deprecaciones y retorna los que
+// están deprecados o eliminados en la versión de Kubernetes objetivo (ej. v1.25.9+rke2r1 o 1.25)
+func Check(objects []manifest.Object, targetVersion string) ([]Finding, error) {
+	target, err := minorVersion(targetVersion)
+	if err != nil {
+		return nil, fmt.Errorf("parsing target kubernetes version: %w", err)
+	}
+
+	var findings []Finding
+	for _, object := range objects {
+		deprecation, ok := Lookup(object.APIVersion, object.Kind)
+		if !ok {
+			continue
+		}
+
+		var status Status
+		switch {
+		case reached(target, deprecation.RemovedIn):
+			status = StatusRemoved
+		case reached(target, deprecation.DeprecatedIn):
+			status = StatusDeprecated
+		default:
+			continue
+		}
+
+		findings = append(findings, Finding{Object: object, Deprecation: deprecation, Status: status})
+	}
+
+	return findings, nil
+}
+
+// reached indica si la versión objetivo es igual o posterior a la versión major.minor indicada
+func reached(target version.Version, minor string) bool {
+	v, err := minorVersion(minor)
+	return err == nil && target.Compare(v) >= 0
+}
+
+// minorVersion interpreta una versión de Kubernetes quedándose solo con major.minor, ya que las
+// APIs se eliminan en versiones minor y los sufijos de distribución (+rke2r1) no participan
+func minorVersion(s string) (version.Version, error) {
+	v, err := version.Parse(s)
+	if err != nil {
+		return version.Version{}, err
+	}
+	return version.Version{Major: v.Major, Minor: v.Minor}, nil
+}
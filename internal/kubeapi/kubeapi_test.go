@@ -0,0 +1,65 @@
+package kubeapi
+
+import (
+	"testing"
+
+	"github.com/start-codex/rke-update-checker/internal/manifest"
+)
+
+func TestCheck(t *testing.T) {
+	cronJob := manifest.Object{APIVersion: "batch/v1beta1", Kind: "CronJob", Name: "backup"}
+
+	tests := []struct {
+		name   string
+		target string
+		object manifest.Object
+		want   Status
+	}{
+		{"before deprecation", "1.20.15", cronJob, ""},
+		{"deprecated at the exact version", "1.21.0", cronJob, StatusDeprecated},
+		{"deprecated before removal", "v1.24.17+rke2r1", cronJob, StatusDeprecated},
+		{"removed at the exact version", "1.25.0", cronJob, StatusRemoved},
+		{"removed with distribution suffix", "v1.25.9+rke2r1", cronJob, StatusRemoved},
+		{"removed with major.minor only", "1.25", cronJob, StatusRemoved},
+		// Las APIs ya no se sirven en las pre-releases de la versión que las elimina
+		{"removed in a pre-release of the removal version", "v1.25.0-rc.1", cronJob, StatusRemoved},
+		{"removed after the removal version", "1.30.2", cronJob, StatusRemoved},
+		{"replacement api", "1.30.2", manifest.Object{APIVersion: "batch/v1", Kind: "CronJob"}, ""},
+		{"unknown group", "1.30.2", manifest.Object{APIVersion: "example.com/v1alpha1", Kind: "CronJob"}, ""},
+		{"unknown version of a known group", "1.30.2", manifest.Object{APIVersion: "batch/v2alpha9", Kind: "CronJob"}, ""},
+		{"kind not deprecated in the group version", "1.30.2", manifest.Object{APIVersion: "apps/v1beta1", Kind: "DaemonSet"}, ""},
+		{"object without apiVersion", "1.30.2", manifest.Object{Kind: "CronJob"}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings, err := Check([]manifest.Object{tt.object}, tt.target)
+			if err != nil {
+				t.Fatalf("Check() unexpected error: %v", err)
+			}
+
+			var got Status
+			if len(findings) > 0 {
+				got = findings[0].Status
+			}
+			if len(findings) > 1 {
+				t.Errorf("Check() = %d findings, want at most 1", len(findings))
+			}
+			if got != tt.want {
+				t.Errorf("Check(%s %s, %q) = %q, want %q", tt.object.APIVersion, tt.object.Kind, tt.target, got, tt.want)
+			}
+			if len(findings) > 0 && findings[0].Blocking() != (tt.want == StatusRemoved) {
+				t.Errorf("Blocking() = %v, want %v", findings[0].Blocking(), tt.want == StatusRemoved)
+			}
+		})
+	}
+}
+
+func TestCheckInvalidTarget(t *testing.T) {
+	objects := []manifest.Object{{APIVersion: "batch/v1beta1", Kind: "CronJob"}}
+	for _, target := range []string{"", "latest"} {
+		if _, err := Check(objects, target); err == nil {
+			t.Errorf("Check(%q) = nil error, want error", target)
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package manifest
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ChangeType clasifica el cambio de un objeto entre dos manifiestos
+type ChangeType string
+
+const (
+	ChangeAdded    ChangeType = "added"
+	ChangeRemoved  ChangeType = "removed"
+	ChangeModified ChangeType = "modified"
+)
+
+// DiffOp indica si una línea del diff se mantiene, se agrega o se elimina
+type DiffOp byte
+
+const (
+	DiffEqual  DiffOp = ' '
+	DiffInsert DiffOp = '+'
+	DiffDelete DiffOp = '-'
+)
+
+// DiffLine es una línea del diff entre dos versiones de un objeto
+type DiffLine struct {
+	Op   DiffOp
+	Text string
+}
+
+// Change representa un objeto agregado, eliminado o modificado entre dos manifiestos
+type Change struct {
+	Type ChangeType
+	// Object es la versión nueva del objeto, o la actual si se elimina
+	Object Object
+	// Lines es el diff línea a línea del YAML de ambas versiones del objeto
+	Lines []DiffLine
+}
+
+// maxDiffCells limita la tabla del LCS; por encima se muestra el objeto completo como reemplazado
+const maxDiffCells = 4 << 20
+
+// Diff compara dos manifiestos a nivel de objetos, identificados por kind, namespace y nombre para
+// que un cambio de apiVersion se vea como una modificación. Los cambios siguen el orden del
+// manifiesto nuevo, seguidos de los objetos eliminados. Retorna también la cantidad de objetos sin cambios.
+func Diff(current, planned []Object) ([]Change, int, error) {
+	currentByKey := make(map[string]Object)
+	for _, object := range current {
+		currentByKey[object.key()] = object
+	}
+
+	var changes []Change
+	unchanged := 0
+	seen := make(map[string]bool)
+
+	for _, object := range planned {
+		key := object.key()
+		seen[key] = true
+
+		before, exists := currentByKey[key]
+		if !exists {
+			lines, err := objectLines(object)
+			if err != nil {
+				return nil, 0, err
+			}
+			changes = append(changes, Change{Type: ChangeAdded, Object: object, Lines: withOp(lines, DiffInsert)})
+			continue
+		}
+
+		beforeLines, err := objectLines(before)
+		if err != nil {
+			return nil, 0, err
+		}
+		afterLines, err := objectLines(object)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		lines := diffLines(beforeLines, afterLines)
+		if !hasChanges(lines) {
+			unchanged++
+			continue
+		}
+		changes = append(changes, Change{Type: ChangeModified, Object: object, Lines: lines})
+	}
+
+	for _, object := range current {
+		if seen[object.key()] {
+			continue
+		}
+		seen[object.key()] = true
+
+		lines, err := objectLines(object)
+		if err != nil {
+			return nil, 0, err
+		}
+		changes = append(changes, Change{Type: ChangeRemoved, Object: object, Lines: withOp(lines, DiffDelete)})
+	}
+
+	return changes, unchanged, nil
+}
+
+// key identifica un objeto dentro de un manifiesto
+func (o Object) key() string {
+	return o.Kind + "/" + o.Namespace + "/" + o.Name
+}
+
+// objectLines serializa el objeto en YAML con las claves ordenadas, para que el diff no
+// dependa del orden en que los templates escriben los campos
+func objectLines(object Object) ([]string, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(object.Content); err != nil {
+		return nil, fmt.Errorf("encoding %s: %w", object, err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("encoding %s: %w", object, err)
+	}
+
+	return strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n"), nil
+}
+
+// diffLines calcula el diff entre dos listas de líneas con la subsecuencia común más larga (LCS).
+// El prefijo y el sufijo comunes se descartan antes para reducir la tabla.
+func diffLines(a, b []string) []DiffLine {
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	lines := withOp(a[:prefix], DiffEqual)
+	lines = append(lines, lcsDiff(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	return append(lines, withOp(a[len(a)-suffix:], DiffEqual)...)
+}
+
+// lcsDiff calcula el diff de dos listas de líneas sin prefijo ni sufijo comunes
+func lcsDiff(a, b []string) []DiffLine {
+	if len(a)*len(b) > maxDiffCells {
+		return append(withOp(a, DiffDelete), withOp(b, DiffInsert)...)
+	}
+
+	// lcs[i][j] es la longitud de la LCS de a[i:] y b[j:]
+	lcs := make([][]int32, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int32, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var lines []DiffLine
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, DiffLine{Op: DiffEqual, Text: a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, DiffLine{Op: DiffDelete, Text: a[i]})
+			i++
+		default:
+			lines = append(lines, DiffLine{Op: DiffInsert, Text: b[j]})
+			j++
+		}
+	}
+	lines = append(lines, withOp(a[i:], DiffDelete)...)
+	return append(lines, withOp(b[j:], DiffInsert)...)
+}
+
+// withOp convierte líneas en líneas de diff con la operación indicada
+func withOp(texts []string, op DiffOp) []DiffLine {
+	lines := make([]DiffLine, 0, len(texts))
+	for _, text := range texts {
+		lines = append(lines, DiffLine{Op: op, Text: text})
+	}
+	return lines
+}
+
+// hasChanges indica si el diff contiene alguna línea agregada o eliminada
+func hasChanges(lines []DiffLine) bool {
+	for _, line := range lines {
+		if line.Op != DiffEqual {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,58 @@
+package manifest
+
+// ContainerImage representa la imagen de un contenedor de un Pod template del manifiesto
+type ContainerImage struct {
+	// Object es el objeto que contiene el Pod template, en formato Kind/nombre
+	Object    string
+	Container string
+	Image     string
+}
+
+// containerFields son los campos del PodSpec que contienen contenedores
+var containerFields = []string{"initContainers", "containers", "ephemeralContainers"}
+
+// Images extrae las imágenes de todos los contenedores de los Pods y Pod templates
+// (Deployment, StatefulSet, DaemonSet, Job, CronJob, ...) de los objetos indicados
+func Images(objects []Object) []ContainerImage {
+	var images []ContainerImage
+
+	for _, object := range objects {
+		spec, ok := podSpec(object)
+		if !ok {
+			continue
+		}
+
+		for _, field := range containerFields {
+			containers, _ := spec[field].([]interface{})
+			for _, c := range containers {
+				container, ok := c.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				image, _ := container["image"].(string)
+				if image == "" {
+					continue
+				}
+				name, _ := container["name"].(string)
+				images = append(images, ContainerImage{
+					Object:    object.String(),
+					Container: name,
+					Image:     image,
+				})
+			}
+		}
+	}
+
+	return images
+}
+
+// podSpec retorna el PodSpec de un Pod o del Pod template de un workload
+func podSpec(object Object) (map[string]interface{}, bool) {
+	switch object.Kind {
+	case "Pod":
+		return nested(object.Content, "spec")
+	case "CronJob":
+		return nested(object.Content, "spec", "jobTemplate", "spec", "template", "spec")
+	}
+	return nested(object.Content, "spec", "template", "spec")
+}
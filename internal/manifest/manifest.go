@@ -0,0 +1,89 @@
+package manifest
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Object representa un objeto de Kubernetes del manifiesto renderizado de un release
+type Object struct {
+	APIVersion string
+	Kind       string
+	Name       string
+	Namespace  string
+	Content    map[string]interface{}
+}
+
+// separator divide los documentos de un manifiesto con la misma expresión que usa Helm
+var separator = regexp.MustCompile(`(?:^|\s*\n)---\s*`)
+
+// Parse separa el manifiesto de un release (documentos YAML separados por ---) en objetos.
+// Cada documento se decodifica por separado y de forma permisiva como lo hace Helm, de modo
+// que, por ejemplo, una clave duplicada no impide leerlo. Los documentos vacíos, como los de
+// templates deshabilitados, se omiten; los inválidos también, y se retornan en el error
+// junto con los objetos del resto del manifiesto.
+func Parse(manifest string) ([]Object, error) {
+	var objects []Object
+	var errs []error
+
+	count := 0
+	for _, document := range separator.Split(manifest, -1) {
+		if strings.TrimSpace(document) == "" {
+			continue
+		}
+		count++
+
+		var content map[string]interface{}
+		if err := yaml.Unmarshal([]byte(document), &content); err != nil {
+			errs = append(errs, fmt.Errorf("decoding %s: %w", documentName(document, count), err))
+			continue
+		}
+		if len(content) == 0 {
+			continue
+		}
+
+		object := Object{Content: content}
+		object.APIVersion, _ = content["apiVersion"].(string)
+		object.Kind, _ = content["kind"].(string)
+		if metadata, ok := content["metadata"].(map[string]interface{}); ok {
+			object.Name, _ = metadata["name"].(string)
+			object.Namespace, _ = metadata["namespace"].(string)
+		}
+		objects = append(objects, object)
+	}
+
+	return objects, errors.Join(errs...)
+}
+
+// documentName identifica un documento del manifiesto por el template del que proviene, según
+// el comentario "# Source:" que agrega Helm, o si no por su posición
+func documentName(document string, position int) string {
+	for _, line := range strings.Split(document, "\n") {
+		if source, ok := strings.CutPrefix(strings.TrimSpace(line), "# Source: "); ok {
+			return source
+		}
+	}
+	return fmt.Sprintf("document %d", position)
+}
+
+// String retorna el objeto en formato Kind/nombre
+func (o Object) String() string {
+	return o.Kind + "/" + o.Name
+}
+
+// nested retorna el mapa anidado en la ruta indicada
+func nested(content map[string]interface{}, fields ...string) (map[string]interface{}, bool) {
+	current := content
+	for _, field := range fields {
+		next, ok := current[field].(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		current = next
+	}
+	return current, true
+}
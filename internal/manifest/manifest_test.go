@@ -0,0 +1,111 @@
+package manifest
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		want     []string
+		wantErr  string
+	}{
+		{
+			name: "documents with source comments",
+			manifest: `---
+# Source: app/templates/service.yaml
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: default
+---
+# Source: app/templates/deployment.yaml
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+`,
+			want: []string{"v1 Service/web default", "apps/v1 Deployment/web "},
+		},
+		{
+			name: "empty and comment-only documents are skipped",
+			manifest: `---
+# Source: app/templates/disabled.yaml
+---
+
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: config
+`,
+			want: []string{"v1 ConfigMap/config "},
+		},
+		{
+			// Helm acepta claves duplicadas y se queda con el último valor
+			name: "duplicate keys",
+			manifest: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: first
+  name: config
+data:
+  key: a
+  key: b
+`,
+			want: []string{"v1 ConfigMap/config "},
+		},
+		{
+			name: "invalid document is reported and the rest is kept",
+			manifest: `---
+# Source: app/templates/broken.yaml
+apiVersion: v1
+kind: ConfigMap
+data: [
+---
+# Source: app/templates/secret.yaml
+apiVersion: v1
+kind: Secret
+metadata:
+  name: creds
+`,
+			want:    []string{"v1 Secret/creds "},
+			wantErr: "decoding app/templates/broken.yaml",
+		},
+		{
+			name:     "invalid document without source",
+			manifest: "kind: ConfigMap\n---\n- a list\n",
+			want:     []string{" ConfigMap/ "},
+			wantErr:  "decoding document 2",
+		},
+		{
+			name:     "separator inside a value",
+			manifest: "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\ndata:\n  header: ---\n",
+			want:     []string{"v1 ConfigMap/config "},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objects, err := Parse(tt.manifest)
+			if tt.wantErr == "" && err != nil {
+				t.Fatalf("Parse() unexpected error: %v", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("Parse() error = %v, want %q", err, tt.wantErr)
+			}
+
+			var got []string
+			for _, object := range objects {
+				got = append(got, object.APIVersion+" "+object.String()+" "+object.Namespace)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package oci
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Media types del manifiesto de un artefacto y de la capa con el contenido de un chart de Helm
+const (
+	manifestMediaType   = "application/vnd.oci.image.manifest.v1+json"
+	chartLayerMediaType = "application/vnd.cncf.helm.chart.content.v1.tar+gzip"
+)
+
+// artifactManifest es el manifiesto OCI de un artefacto; solo interesan sus capas
+type artifactManifest struct {
+	Layers []struct {
+		MediaType string `json:"mediaType"`
+		Digest    string `json:"digest"`
+	} `json:"layers"`
+}
+
+// PullChart descarga el archivo .tgz de un chart publicado con el tag indicado. El llamador
+// debe cerrar el contenido retornado.
+func (c *Client) PullChart(ctx context.Context, ref Reference, tag string) (io.ReadCloser, error) {
+	resp, err := c.get(ctx, ref, c.baseURL(ref.Registry)+"/v2/"+ref.Repository+"/manifests/"+tag, manifestMediaType)
+	if err != nil {
+		return nil, err
+	}
+
+	var manifest artifactManifest
+	err = json.NewDecoder(resp.Body).Decode(&manifest)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("decoding manifest: %w", err)
+	}
+
+	for _, layer := range manifest.Layers {
+		if layer.MediaType != chartLayerMediaType {
+			continue
+		}
+
+		blob, err := c.get(ctx, ref, c.baseURL(ref.Registry)+"/v2/"+ref.Repository+"/blobs/"+layer.Digest)
+		if err != nil {
+			return nil, err
+		}
+		return blob.Body, nil
+	}
+
+	return nil, fmt.Errorf("%s:%s is not a helm chart", ref, tag)
+}
@@ -0,0 +1,297 @@
+package oci
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ErrUnauthorized indica que el registry rechazó el acceso, ya sea por no tener credenciales
+// o porque las configuradas no son válidas o no permiten leer el repositorio
+var ErrUnauthorized = errors.New("registry authentication required")
+
+// Client consulta registries mediante la API de distribución OCI (/v2/)
+type Client struct {
+	httpClient *http.Client
+	username   string
+	password   string
+	token      string
+	plainHTTP  bool
+}
+
+// Options configura el acceso a un registry
+type Options struct {
+	Username string
+	Password string
+	// Token se envía directamente como token bearer, sin pasar por el servicio de tokens del registry
+	Token                 string
+	InsecureSkipTLSVerify bool
+	// PlainHTTP usa http en lugar de https; siempre se usa para localhost
+	PlainHTTP bool
+}
+
+// NewClient crea un cliente de la API de distribución OCI
+func NewClient(opts Options) *Client {
+	return &Client{
+		httpClient: &http.Client{
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: opts.InsecureSkipTLSVerify},
+			},
+		},
+		username:  opts.Username,
+		password:  opts.Password,
+		token:     opts.Token,
+		plainHTTP: opts.PlainHTTP,
+	}
+}
+
+// tagList es la respuesta de /v2/<name>/tags/list
+type tagList struct {
+	Name string   `json:"name"`
+	Tags []string `json:"tags"`
+}
+
+// ListTags lista todos los tags de un repositorio siguiendo la paginación del header Link
+func (c *Client) ListTags(ctx context.Context, ref Reference) ([]string, error) {
+	var tags []string
+
+	next := c.baseURL(ref.Registry) + "/v2/" + ref.Repository + "/tags/list?n=1000"
+	for next != "" {
+		resp, err := c.get(ctx, ref, next)
+		if err != nil {
+			return nil, err
+		}
+
+		var page tagList
+		err = json.NewDecoder(resp.Body).Decode(&page)
+		link := resp.Header.Get("Link")
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("decoding tag list: %w", err)
+		}
+
+		tags = append(tags, page.Tags...)
+
+		next, err = c.nextPage(ref.Registry, link)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return tags, nil
+}
+
+// get ejecuta una petición GET autenticándose con el registry si lo requiere
+func (c *Client) get(ctx context.Context, ref Reference, rawURL string, accept ...string) (*http.Response, error) {
+	newRequest := func() (*http.Request, error) {
+		req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range accept {
+			req.Header.Add("Accept", a)
+		}
+		return req, nil
+	}
+
+	req, err := newRequest()
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	// El registry indica en WWW-Authenticate cómo autenticarse
+	if resp.StatusCode == http.StatusUnauthorized {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+
+		req, err = newRequest()
+		if err != nil {
+			return nil, err
+		}
+		if err := c.authorize(ctx, req, ref, challenge); err != nil {
+			return nil, err
+		}
+
+		resp, err = c.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		err := fmt.Errorf("registry %s returned status %d for %s", ref.Registry, resp.StatusCode, ref.Repository)
+		if isAuthStatus(resp.StatusCode) {
+			err = fmt.Errorf("%w: %w", err, ErrUnauthorized)
+		}
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// authorize responde a un challenge Basic o Bearer del registry
+func (c *Client) authorize(ctx context.Context, req *http.Request, ref Reference, challenge string) error {
+	scheme, params := parseChallenge(challenge)
+
+	switch strings.ToLower(scheme) {
+	case "basic":
+		req.SetBasicAuth(c.username, c.password)
+		return nil
+	case "bearer":
+		if c.token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.token)
+			return nil
+		}
+		token, err := c.fetchToken(ctx, params, ref)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	}
+
+	return fmt.Errorf("unsupported registry auth challenge %q", challenge)
+}
+
+// tokenResponse es la respuesta del servicio de tokens del registry
+type tokenResponse struct {
+	Token       string `json:"token"`
+	AccessToken string `json:"access_token"`
+}
+
+// fetchToken obtiene un token bearer del realm indicado en el challenge
+func (c *Client) fetchToken(ctx context.Context, params map[string]string, ref Reference) (string, error) {
+	realm := params["realm"]
+	if realm == "" {
+		return "", fmt.Errorf("bearer challenge without realm")
+	}
+
+	tokenURL, err := url.Parse(realm)
+	if err != nil {
+		return "", fmt.Errorf("invalid token realm %q: %w", realm, err)
+	}
+
+	query := tokenURL.Query()
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	scope := params["scope"]
+	if scope == "" {
+		scope = "repository:" + ref.Repository + ":pull"
+	}
+	query.Set("scope", scope)
+	tokenURL.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", tokenURL.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if c.username != "" || c.password != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("requesting registry token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if isAuthStatus(resp.StatusCode) {
+		return "", fmt.Errorf("token service returned status %d: %w", resp.StatusCode, ErrUnauthorized)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token service returned status %d", resp.StatusCode)
+	}
+
+	var token tokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("decoding registry token: %w", err)
+	}
+
+	if token.Token != "" {
+		return token.Token, nil
+	}
+	return token.AccessToken, nil
+}
+
+// isAuthStatus indica si el código de estado HTTP corresponde a un acceso rechazado
+func isAuthStatus(code int) bool {
+	return code == http.StatusUnauthorized || code == http.StatusForbidden
+}
+
+// nextPage resuelve la URL de la siguiente página desde el header Link (RFC 5988)
+func (c *Client) nextPage(registry, link string) (string, error) {
+	if link == "" {
+		return "", nil
+	}
+
+	start, end := strings.Index(link, "<"), strings.Index(link, ">")
+	if start < 0 || end < start {
+		return "", fmt.Errorf("invalid Link header %q", link)
+	}
+
+	next, err := url.Parse(link[start+1 : end])
+	if err != nil {
+		return "", fmt.Errorf("invalid Link header %q: %w", link, err)
+	}
+
+	base, err := url.Parse(c.baseURL(registry))
+	if err != nil {
+		return "", err
+	}
+
+	return base.ResolveReference(next).String(), nil
+}
+
+// baseURL retorna la URL base del registry
+func (c *Client) baseURL(registry string) string {
+	if registry == DockerHub {
+		registry = dockerHubAPI
+	}
+	if c.plainHTTP || isLocalhost(registry) {
+		return "http://" + registry
+	}
+	return "https://" + registry
+}
+
+// isLocalhost indica si el registry es local, donde se usa http sin TLS como hace Docker
+func isLocalhost(registry string) bool {
+	host := registry
+	if i := strings.LastIndex(host, ":"); i >= 0 {
+		host = host[:i]
+	}
+	return host == "localhost" || host == "127.0.0.1"
+}
+
+// parseChallenge interpreta un header WWW-Authenticate como `Bearer realm="...",service="..."`
+func parseChallenge(challenge string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	params := make(map[string]string)
+
+	for rest != "" {
+		var key, value string
+		key, rest, _ = strings.Cut(strings.TrimLeft(rest, " ,"), "=")
+		if strings.HasPrefix(rest, `"`) {
+			value, rest, _ = strings.Cut(rest[1:], `"`)
+		} else {
+			value, rest, _ = strings.Cut(rest, ",")
+		}
+		if key != "" {
+			params[strings.ToLower(strings.TrimSpace(key))] = value
+		}
+	}
+
+	return scheme, params
+}
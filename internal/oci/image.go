@@ -0,0 +1,75 @@
+package oci
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DockerHub es el registry por defecto de las imágenes sin registry explícito
+const DockerHub = "docker.io"
+
+// dockerHubAPI es el host que sirve la API de distribución de Docker Hub
+const dockerHubAPI = "registry-1.docker.io"
+
+// Image identifica una imagen de contenedor: su repositorio y el tag y/o digest referenciados
+type Image struct {
+	Reference
+	Tag    string
+	Digest string
+}
+
+// ParseImage interpreta una referencia de imagen como las usadas en los Pods, normalizando
+// las imágenes de Docker Hub (nginx -> docker.io/library/nginx:latest)
+func ParseImage(image string) (Image, error) {
+	var parsed Image
+
+	name := strings.TrimSpace(image)
+	if name == "" {
+		return Image{}, fmt.Errorf("empty image reference")
+	}
+
+	if i := strings.Index(name, "@"); i >= 0 {
+		parsed.Digest = name[i+1:]
+		name = name[:i]
+	}
+
+	// El tag es lo que sigue al último ':' siempre que no forme parte del host (registry:puerto)
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		parsed.Tag = name[i+1:]
+		name = name[:i]
+	}
+	if parsed.Tag == "" && parsed.Digest == "" {
+		parsed.Tag = "latest"
+	}
+
+	// El primer componente es un registry si parece un host
+	registry, repository, found := strings.Cut(name, "/")
+	if !found || !(strings.ContainsAny(registry, ".:") || registry == "localhost") {
+		registry, repository = DockerHub, name
+	}
+	if registry == "index.docker.io" {
+		registry = DockerHub
+	}
+	if registry == DockerHub && !strings.Contains(repository, "/") {
+		repository = "library/" + repository
+	}
+
+	if repository == "" {
+		return Image{}, fmt.Errorf("invalid image reference %q", image)
+	}
+
+	parsed.Reference = Reference{Registry: registry, Repository: repository}
+	return parsed, nil
+}
+
+// String retorna la referencia normalizada de la imagen
+func (i Image) String() string {
+	s := i.Reference.String()
+	if i.Tag != "" {
+		s += ":" + i.Tag
+	}
+	if i.Digest != "" {
+		s += "@" + i.Digest
+	}
+	return s
+}
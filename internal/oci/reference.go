@@ -0,0 +1,55 @@
+package oci
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Scheme es el prefijo de las referencias a charts publicados como artefactos OCI
+const Scheme = "oci://"
+
+// Reference identifica un repositorio dentro de un registry OCI. Repository es vacío cuando
+// la referencia apunta a la raíz del registry.
+type Reference struct {
+	Registry   string
+	Repository string
+}
+
+// ParseReference interpreta una referencia registry/repository o solo registry (la raíz del
+// registry, como oci://localhost:5000), con o sin el prefijo oci://
+func ParseReference(ref string) (Reference, error) {
+	clean := strings.TrimSuffix(strings.TrimPrefix(ref, Scheme), "/")
+
+	registry, repository, _ := strings.Cut(clean, "/")
+	if registry == "" || strings.HasPrefix(repository, "/") || strings.HasSuffix(repository, "/") || strings.Contains(repository, "//") {
+		return Reference{}, fmt.Errorf("invalid OCI reference %q: expected registry[/repository]", ref)
+	}
+
+	return Reference{Registry: registry, Repository: repository}, nil
+}
+
+// IsRoot indica si la referencia apunta a la raíz del registry y no a un repositorio
+func (r Reference) IsRoot() bool {
+	return r.Repository == ""
+}
+
+// Child retorna la referencia a un repositorio anidado dentro de este
+func (r Reference) Child(name string) Reference {
+	if r.IsRoot() {
+		return Reference{Registry: r.Registry, Repository: name}
+	}
+	return Reference{Registry: r.Registry, Repository: r.Repository + "/" + name}
+}
+
+// Name retorna el último componente del repositorio
+func (r Reference) Name() string {
+	return r.Repository[strings.LastIndex(r.Repository, "/")+1:]
+}
+
+// String retorna la referencia en formato registry/repository
+func (r Reference) String() string {
+	if r.IsRoot() {
+		return r.Registry
+	}
+	return r.Registry + "/" + r.Repository
+}
@@ -0,0 +1,42 @@
+package oci
+
+import "testing"
+
+func TestParseReference(t *testing.T) {
+	tests := []struct {
+		input     string
+		want      Reference
+		wantChild string
+		wantErr   bool
+	}{
+		{input: "oci://ghcr.io/stefanprodan/charts/podinfo", want: Reference{"ghcr.io", "stefanprodan/charts/podinfo"}, wantChild: "ghcr.io/stefanprodan/charts/podinfo/app"},
+		{input: "registry-1.docker.io/bitnamicharts/", want: Reference{"registry-1.docker.io", "bitnamicharts"}, wantChild: "registry-1.docker.io/bitnamicharts/app"},
+		{input: "oci://localhost:5000", want: Reference{"localhost:5000", ""}, wantChild: "localhost:5000/app"},
+		{input: "oci://registry.example.com:8443/", want: Reference{"registry.example.com:8443", ""}, wantChild: "registry.example.com:8443/app"},
+
+		{input: "oci://", wantErr: true},
+		{input: "oci:///charts", wantErr: true},
+		{input: "oci://ghcr.io//charts", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseReference(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseReference(%q) = %v, want error", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseReference(%q) unexpected error: %v", tt.input, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseReference(%q) = %#v, want %#v", tt.input, got, tt.want)
+			}
+			if child := got.Child("app"); child.String() != tt.wantChild || child.Name() != "app" {
+				t.Errorf("Child(app) = %s (name %s), want %s", child, child.Name(), tt.wantChild)
+			}
+		})
+	}
+}
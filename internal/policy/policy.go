@@ -0,0 +1,135 @@
+package policy
+
+import (
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/Masterminds/semver/v3"
+	"sigs.k8s.io/yaml"
+)
+
+// Policy contiene las reglas de fijación de versiones de charts
+type Policy struct {
+	Rules []Rule `json:"rules"`
+}
+
+// Trigger indica qué cambio entre la versión instalada y la objetivo cuenta como actualización
+type Trigger string
+
+const (
+	// TriggerChart reporta una actualización cuando hay una versión de chart más nueva (por defecto)
+	TriggerChart Trigger = "chart"
+	// TriggerAppVersion reporta una actualización solo cuando cambia la versión de la aplicación
+	TriggerAppVersion Trigger = "appVersion"
+	// TriggerAny reporta una actualización con cualquiera de los dos cambios
+	TriggerAny Trigger = "any"
+)
+
+// Rule fija las versiones permitidas para los releases que coinciden con sus selectores.
+// Los selectores aceptan patrones glob (path.Match) y un selector vacío coincide con todo.
+type Rule struct {
+	Cluster    string  `json:"cluster"`
+	Namespace  string  `json:"namespace"`
+	Release    string  `json:"release"`
+	Chart      string  `json:"chart"`
+	Constraint string  `json:"constraint"`
+	Trigger    Trigger `json:"trigger"`
+
+	constraints *semver.Constraints
+}
+
+// Load lee y valida un archivo de políticas en formato YAML
+func Load(filename string) (*Policy, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("reading policy file: %w", err)
+	}
+
+	var p Policy
+	if err := yaml.UnmarshalStrict(data, &p); err != nil {
+		return nil, fmt.Errorf("parsing policy file: %w", err)
+	}
+
+	for i := range p.Rules {
+		rule := &p.Rules[i]
+		if rule.Constraint == "" {
+			return nil, fmt.Errorf("rule %d: constraint is required", i)
+		}
+
+		constraints, err := semver.NewConstraint(rule.Constraint)
+		if err != nil {
+			return nil, fmt.Errorf("rule %d: invalid constraint %q: %w", i, rule.Constraint, err)
+		}
+		rule.constraints = constraints
+
+		switch rule.Trigger {
+		case "":
+			rule.Trigger = TriggerChart
+		case TriggerChart, TriggerAppVersion, TriggerAny:
+		default:
+			return nil, fmt.Errorf("rule %d: invalid trigger %q (chart, appVersion or any)", i, rule.Trigger)
+		}
+
+		for _, pattern := range []string{rule.Cluster, rule.Namespace, rule.Release, rule.Chart} {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("rule %d: invalid selector %q: %w", i, pattern, err)
+			}
+		}
+	}
+
+	return &p, nil
+}
+
+// Match retorna la primera regla que coincide con el release indicado
+func (p *Policy) Match(cluster, namespace, release, chart string) (*Rule, bool) {
+	if p == nil {
+		return nil, false
+	}
+
+	for i := range p.Rules {
+		rule := &p.Rules[i]
+		if selectorMatches(rule.Cluster, cluster) &&
+			selectorMatches(rule.Namespace, namespace) &&
+			selectorMatches(rule.Release, release) &&
+			selectorMatches(rule.Chart, chart) {
+			return rule, true
+		}
+	}
+
+	return nil, false
+}
+
+// Allows verifica si una versión cumple la restricción de la regla
+func (r *Rule) Allows(v string) bool {
+	parsed, err := semver.NewVersion(v)
+	if err != nil {
+		return false
+	}
+	return r.constraints.Check(parsed)
+}
+
+// Triggers indica si los cambios hacia la versión objetivo cuentan como actualización según
+// el trigger de la regla; una regla nil usa TriggerChart
+func (r *Rule) Triggers(chartNewer, appVersionNewer bool) bool {
+	if r == nil {
+		return chartNewer
+	}
+
+	switch r.Trigger {
+	case TriggerAppVersion:
+		return appVersionNewer
+	case TriggerAny:
+		return chartNewer || appVersionNewer
+	}
+	return chartNewer
+}
+
+// selectorMatches verifica un selector glob; un selector vacío coincide con cualquier valor
+func selectorMatches(pattern, value string) bool {
+	if pattern == "" {
+		return true
+	}
+	matched, err := path.Match(pattern, value)
+	return err == nil && matched
+}
@@ -0,0 +1,228 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// load escribe el archivo de políticas en un directorio temporal y lo carga
+func load(t *testing.T, content string) (*Policy, error) {
+	t.Helper()
+
+	filename := filepath.Join(t.TempDir(), "policy.yaml")
+	if err := os.WriteFile(filename, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return Load(filename)
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name: "valid",
+			content: `
+rules:
+  - cluster: prod-*
+    chart: cert-manager
+    constraint: ">=1.12 <1.14"
+  - chart: rancher-*
+    constraint: "~2.8"
+    trigger: appVersion
+`,
+		},
+		{
+			name:    "missing constraint",
+			content: "rules:\n  - chart: nginx\n",
+			wantErr: "rule 0: constraint is required",
+		},
+		{
+			name:    "malformed constraint",
+			content: "rules:\n  - chart: nginx\n    constraint: \">=1.x.y\"\n",
+			wantErr: `rule 0: invalid constraint ">=1.x.y"`,
+		},
+		{
+			name:    "constraint with too many components",
+			content: "rules:\n  - constraint: \"1.2.3.4\"\n",
+			wantErr: `rule 0: invalid constraint "1.2.3.4"`,
+		},
+		{
+			name:    "second rule invalid",
+			content: "rules:\n  - constraint: \"^1\"\n  - constraint: \"not-a-version\"\n",
+			wantErr: "rule 1: invalid constraint",
+		},
+		{
+			name:    "invalid trigger",
+			content: "rules:\n  - constraint: \"^1\"\n    trigger: image\n",
+			wantErr: `rule 0: invalid trigger "image"`,
+		},
+		{
+			name:    "invalid selector",
+			content: "rules:\n  - cluster: \"prod-[\"\n    constraint: \"^1\"\n",
+			wantErr: `rule 0: invalid selector "prod-["`,
+		},
+		{
+			name:    "unknown field",
+			content: "rules:\n  - chrt: nginx\n    constraint: \"^1\"\n",
+			wantErr: "parsing policy file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := load(t, tt.content)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Load() unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Load() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	p, err := load(t, `
+rules:
+  - cluster: prod-eu
+    chart: cert-manager
+    constraint: "~1.12"
+  - cluster: prod-*
+    constraint: "<2"
+  - chart: cert-manager
+    constraint: "~1.14"
+  - namespace: kube-system
+    release: "rke2-*"
+    constraint: "*"
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name                             string
+		cluster, namespace, release, chr string
+		wantConstraint                   string
+	}{
+		{"cluster and chart scoped rule first", "prod-eu", "cert-manager", "cert-manager", "cert-manager", "~1.12"},
+		{"cluster scoped rule before chart scoped rule", "prod-us", "cert-manager", "cert-manager", "cert-manager", "<2"},
+		{"chart scoped rule on other clusters", "dev", "cert-manager", "cert-manager", "cert-manager", "~1.14"},
+		{"namespace and release globs", "dev", "kube-system", "rke2-coredns", "rke2-coredns", "*"},
+		{"no matching rule", "dev", "default", "nginx", "nginx", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, ok := p.Match(tt.cluster, tt.namespace, tt.release, tt.chr)
+			if tt.wantConstraint == "" {
+				if ok {
+					t.Fatalf("Match() = %q, want no rule", rule.Constraint)
+				}
+				return
+			}
+			if !ok {
+				t.Fatalf("Match() found no rule, want %q", tt.wantConstraint)
+			}
+			if rule.Constraint != tt.wantConstraint {
+				t.Errorf("Match() = %q, want %q", rule.Constraint, tt.wantConstraint)
+			}
+		})
+	}
+
+	var nilPolicy *Policy
+	if _, ok := nilPolicy.Match("prod-eu", "default", "app", "app"); ok {
+		t.Error("Match() on nil policy found a rule")
+	}
+}
+
+func TestAllows(t *testing.T) {
+	p, err := load(t, "rules:\n  - constraint: \">=1.12 <1.14\"\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rule := &p.Rules[0]
+
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{"1.12.0", true},
+		{"v1.13.9", true},
+		{"1.14.0", false},
+		{"1.11.9", false},
+		{"1.13.0-rc.1", false},
+		{"unknown", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			if got := rule.Allows(tt.version); got != tt.want {
+				t.Errorf("Allows(%q) = %v, want %v", tt.version, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTriggers(t *testing.T) {
+	p, err := load(t, `
+rules:
+  - chart: default-trigger
+    constraint: "*"
+  - chart: chart-trigger
+    constraint: "*"
+    trigger: chart
+  - chart: app-trigger
+    constraint: "*"
+    trigger: appVersion
+  - chart: any-trigger
+    constraint: "*"
+    trigger: any
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rule := func(chart string) *Rule {
+		r, ok := p.Match("", "", "", chart)
+		if !ok {
+			t.Fatalf("no rule for %s", chart)
+		}
+		return r
+	}
+
+	tests := []struct {
+		name                        string
+		rule                        *Rule
+		chartNewer, appVersionNewer bool
+		want                        bool
+	}{
+		{"no rule with chart change", nil, true, false, true},
+		{"no rule with app change only", nil, false, true, false},
+		{"default is chart", rule("default-trigger"), true, false, true},
+		{"default ignores app change only", rule("default-trigger"), false, true, false},
+		{"chart with chart change", rule("chart-trigger"), true, false, true},
+		{"chart with app change only", rule("chart-trigger"), false, true, false},
+		{"appVersion with chart change only", rule("app-trigger"), true, false, false},
+		{"appVersion with app change", rule("app-trigger"), false, true, true},
+		{"appVersion with both changes", rule("app-trigger"), true, true, true},
+		{"any with chart change", rule("any-trigger"), true, false, true},
+		{"any with app change", rule("any-trigger"), false, true, true},
+		{"any without changes", rule("any-trigger"), false, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.Triggers(tt.chartNewer, tt.appVersionNewer); got != tt.want {
+				t.Errorf("Triggers(%v, %v) = %v, want %v", tt.chartNewer, tt.appVersionNewer, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,25 @@
+package rancher
+
+import (
+	"log"
+
+	"github.com/start-codex/rke-update-checker/internal/kubeapi"
+	"github.com/start-codex/rke-update-checker/internal/manifest"
+)
+
+// checkAPIs busca en el manifiesto de un release los objetos cuyo apiVersion está deprecado o
+// eliminado en la versión de Kubernetes objetivo
+func checkAPIs(releaseManifest, targetKubeVersion string, logger *log.Logger) []kubeapi.Finding {
+	objects, err := manifest.Parse(releaseManifest)
+	if err != nil {
+		// Los documentos inválidos se omiten y el resto del manifiesto se revisa igual
+		logger.Printf("Error parsing manifest: %v", err)
+	}
+
+	findings, err := kubeapi.Check(objects, targetKubeVersion)
+	if err != nil {
+		logger.Printf("Error checking deprecated APIs: %v", err)
+		return nil
+	}
+	return findings
+}
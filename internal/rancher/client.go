@@ -2,19 +2,24 @@ package rancher
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/rancher/norman/clientbase"
 	"github.com/rancher/norman/types"
 	rancherClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
 
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/client-go/kubernetes"
-	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/rest"
 
 	"github.com/start-codex/rke-update-checker/internal/chart"
 	"github.com/start-codex/rke-update-checker/internal/helm"
+	"github.com/start-codex/rke-update-checker/internal/kubeapi"
+	"github.com/start-codex/rke-update-checker/internal/policy"
 	"github.com/start-codex/rke-update-checker/internal/version"
 )
 
@@ -23,21 +28,127 @@ type Config struct {
 	URL     string
 	Token   string
 	Verbose bool
+
+	// Charts y clusters que aceptan versiones pre-release (alpha/beta/rc)
+	PreReleaseCharts   []string
+	PreReleaseClusters []string
+
+	// Archivo YAML con reglas de fijación de versiones (opcional)
+	PolicyFile string
+
+	// Archivo de repositorios compatible con repositories.yaml de Helm (opcional)
+	RepositoriesFile string
+
+	// Archivo YAML con las bases de datos del driver SQL de Helm por cluster (opcional)
+	HelmSQLConfigFile string
+
+	// Cantidad de clusters procesados en paralelo y tiempo máximo por cluster (0 = sin límite)
+	Concurrency    int
+	ClusterTimeout time.Duration
+
+	// Timeouts por operación (0 = sin límite): generación de kubeconfig, listado de
+	// secrets de Helm y descarga de cada índice de charts
+	KubeconfigTimeout time.Duration
+	SecretsTimeout    time.Duration
+	IndexTimeout      time.Duration
+
+	// Directorio donde persistir los índices de charts entre ejecuciones (vacío = solo en memoria)
+	// y tiempo durante el cual un índice persistido se usa sin revalidar
+	CacheDir string
+	CacheTTL time.Duration
+
+	// Comparar las imágenes de los manifiestos con los tags publicados en sus registries
+	CheckImages bool
+
+	// Descargar la versión objetivo de los charts con actualización para comparar sus values
+	// por defecto y su values.schema.json con los values de cada release
+	CheckValues bool
+
+	// Versión de Kubernetes contra la que revisar las APIs deprecadas de los manifiestos
+	// (vacío = la versión actual de cada cluster)
+	TargetKubeVersion string
 }
 
 // Client encapsula el cliente de Rancher y funcionalidad relacionada
 type Client struct {
-	client  *rancherClient.Client
-	config  *Config
+	client        *rancherClient.Client
+	config        *Config
+	channelPolicy chart.ChannelPolicy
+	policy        *policy.Policy
+	sqlConfig     *helm.SQLConfig
+
+	// Fetcher compartido por todos los clusters para descargar cada índice una sola vez
+	fetcher *chart.Fetcher
+	// Verificador de imágenes, nil si CheckImages está desactivado
+	images *imageChecker
+
+	// Versión del servidor Rancher, usada para la anotación catalog.cattle.io/rancher-version
+	serverVersion string
 }
 
+// Blocker indica qué impide instalar las versiones más nuevas de un chart
+type Blocker string
+
+const (
+	BlockedByKubernetes Blocker = "kubernetes"
+	BlockedByRancher    Blocker = "rancher"
+)
+
 // HelmApp representa una aplicación Helm con información de actualización
 type HelmApp struct {
 	Release         helm.Release
 	CurrentVersion  string
 	LatestVersion   string
 	UpdateAvailable bool
-	Cluster         string
+	UpdateType      version.Change
+	VersionsBehind  int
+	// Versión objetivo de la actualización: la última instalable en el cluster y permitida por la política
+	TargetVersion string
+	// Versiones publicadas entre la instalada y la última
+	IntermediateVersions int
+	// Última versión dentro de la línea major.minor y major actuales ("" si no hay más nuevas)
+	LatestInMinor string
+	LatestInMajor string
+	Channel       chart.Channel
+	Cluster       string
+
+	// Versión de Kubernetes del cluster y última versión del chart instalable en ella
+	// (también según la versión del servidor Rancher)
+	KubeVersion       string
+	LatestInstallable string
+	// Si hay versiones más nuevas pero ninguna instalable, la versión que las bloquea
+	BlockedBy Blocker
+
+	// El chart instalado fue deprecado upstream
+	Deprecated bool
+
+	// Fijación de versión según la política; PolicyConstraint vacío si ninguna regla aplica
+	PolicyConstraint string
+	PolicyCompliant  bool
+	LatestAllowed    string
+
+	// Estado del release según su última revisión y la última desplegada
+	Health helm.Health
+
+	// Versión de la aplicación instalada y de la versión objetivo, y tipo de cambio entre ambas
+	CurrentAppVersion string
+	LatestAppVersion  string
+	AppUpdateType     version.Change
+	// Hay una versión de chart más nueva que no cuenta como actualización según el trigger de la política
+	ChartOnlyUpdate bool
+
+	// Estado de actualización de las dependencias (subcharts) del chart instalado
+	Dependencies []DependencyUpdate
+	// Estado de actualización de las imágenes del manifiesto (solo con CheckImages)
+	Images []ImageUpdate
+
+	// Cambios en los values entre el chart instalado y la versión objetivo (solo con CheckValues
+	// y si hay una actualización; nil si no pudo descargarse la versión objetivo)
+	Values *helm.ValuesDrift
+
+	// Versión de Kubernetes objetivo y objetos del manifiesto con APIs deprecadas o eliminadas en ella
+	TargetKubeVersion string
+	APIs              []kubeapi.Finding
 }
 
 // NewClient crea un nuevo cliente de Rancher
@@ -51,157 +162,369 @@ func NewClient(config *Config) (*Client, error) {
 		return nil, fmt.Errorf("creating rancher client: %w", err)
 	}
 
+	var versionPolicy *policy.Policy
+	if config.PolicyFile != "" {
+		versionPolicy, err = policy.Load(config.PolicyFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading policy: %w", err)
+		}
+	}
+
+	var repositories []chart.Repository
+	if config.RepositoriesFile != "" {
+		repoFile, err := chart.LoadRepositoryFile(config.RepositoriesFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading repositories: %w", err)
+		}
+		repositories = repoFile.Repositories
+	}
+
+	var sqlConfig *helm.SQLConfig
+	if config.HelmSQLConfigFile != "" {
+		sqlConfig, err = helm.LoadSQLConfig(config.HelmSQLConfigFile)
+		if err != nil {
+			return nil, fmt.Errorf("loading helm sql config: %w", err)
+		}
+	}
+
+	if config.TargetKubeVersion != "" {
+		if _, err := version.Parse(config.TargetKubeVersion); err != nil {
+			return nil, fmt.Errorf("invalid target kubernetes version: %w", err)
+		}
+	}
+
+	var images *imageChecker
+	if config.CheckImages {
+		images = newImageChecker(repositories, config.IndexTimeout)
+	}
+
 	return &Client{
-		client: client,
-		config: config,
+		client:        client,
+		config:        config,
+		channelPolicy: chart.NewChannelPolicy(config.PreReleaseCharts, config.PreReleaseClusters),
+		policy:        versionPolicy,
+		sqlConfig:     sqlConfig,
+		images:        images,
+		fetcher:       chart.NewFetcher(client, repositories, chart.NewIndexCache(config.CacheDir, config.CacheTTL), config.IndexTimeout),
 	}, nil
 }
 
 // ListClusters lista todos los clusters disponibles
-func (c *Client) ListClusters() ([]rancherClient.Cluster, error) {
-	clusterList, err := c.client.Cluster.List(&types.ListOpts{})
+func (c *Client) ListClusters(ctx context.Context) ([]rancherClient.Cluster, error) {
+	clusterList, err := callWithContext(ctx, func() (*rancherClient.ClusterCollection, error) {
+		return c.client.Cluster.List(&types.ListOpts{})
+	})
 	if err != nil {
 		return nil, fmt.Errorf("listing clusters: %w", err)
 	}
 	return clusterList.Data, nil
 }
 
-// ProcessAllClusters procesa todos los clusters y retorna todas las aplicaciones Helm
-func (c *Client) ProcessAllClusters(clusters []rancherClient.Cluster) ([]HelmApp, error) {
-	var allApps []HelmApp
+// ServerVersion obtiene la versión del servidor Rancher desde el setting server-version
+func (c *Client) ServerVersion(ctx context.Context) (string, error) {
+	setting, err := callWithContext(ctx, func() (*rancherClient.Setting, error) {
+		return c.client.Setting.ByID("server-version")
+	})
+	if err != nil {
+		return "", fmt.Errorf("getting server-version setting: %w", err)
+	}
+	return setting.Value, nil
+}
 
-	for _, cluster := range clusters {
+// loadServerVersion guarda la versión del servidor Rancher para filtrar las versiones de los
+// charts por su anotación. Si no se puede obtener o es de desarrollo se omite ese filtro.
+func (c *Client) loadServerVersion(ctx context.Context) {
+	serverVersion, err := c.ServerVersion(ctx)
+	if err != nil && c.config.Verbose {
+		log.Printf("Error getting Rancher server version: %v", err)
+	}
+	if serverVersion != "" && !chart.IsReleaseVersion(serverVersion) {
 		if c.config.Verbose {
-			log.Printf("Processing cluster: %s", cluster.Name)
+			log.Printf("Rancher server version %q is not a release version, ignoring %s", serverVersion, chart.AnnotationRancherVersion)
 		}
+		serverVersion = ""
+	}
+	c.serverVersion = serverVersion
+}
 
-		apps, err := c.processCluster(cluster)
-		if err != nil {
-			log.Printf("Error processing cluster %s: %v", cluster.Name, err)
-			continue
+// ProcessAllClusters procesa todos los clusters en paralelo y retorna todas las aplicaciones Helm
+// en el mismo orden que la lista de clusters. Los errores de cada cluster se agregan en el
+// error retornado sin descartar los resultados de los demás clusters. Si el contexto se cancela
+// no se inician más clusters y se retornan los resultados parciales obtenidos hasta entonces.
+func (c *Client) ProcessAllClusters(ctx context.Context, clusters []rancherClient.Cluster) ([]HelmApp, error) {
+	c.loadServerVersion(ctx)
+
+	workers := c.config.Concurrency
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > len(clusters) {
+		workers = len(clusters)
+	}
+
+	// Cada worker escribe solo en la posición de su cluster, lo que mantiene el orden
+	results := make([]clusterResult, len(clusters))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = c.runCluster(ctx, clusters[i])
+			}
+		}()
+	}
+
+dispatch:
+	for i := range clusters {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break dispatch
 		}
+	}
+	close(jobs)
+	wg.Wait()
 
-		allApps = append(allApps, apps...)
+	var allApps []HelmApp
+	var errs []error
+	for i, result := range results {
+		allApps = append(allApps, result.apps...)
+		if result.err != nil {
+			errs = append(errs, fmt.Errorf("cluster %s: %w", clusters[i].Name, result.err))
+		}
 	}
 
-	return allApps, nil
+	return allApps, errors.Join(errs...)
 }
 
-// processCluster procesa un cluster individual
-func (c *Client) processCluster(cluster rancherClient.Cluster) ([]HelmApp, error) {
-	// Cargar charts disponibles una sola vez por cluster
-	availableCharts, err := c.getAvailableCharts(cluster)
-	if err != nil {
-		if c.config.Verbose {
-			log.Printf("Error loading available charts for cluster %s: %v", cluster.Name, err)
-		}
-		availableCharts = []chart.Chart{} // Fallback
+// clusterResult contiene el resultado del procesamiento de un cluster
+type clusterResult struct {
+	apps []HelmApp
+	err  error
+}
+
+// runCluster procesa un cluster aplicando el timeout por cluster
+func (c *Client) runCluster(ctx context.Context, cluster rancherClient.Cluster) clusterResult {
+	if c.config.ClusterTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.config.ClusterTimeout)
+		defer cancel()
 	}
 
-	// Obtener releases de Helm
-	releases, err := c.getHelmReleases(cluster)
+	logger := c.clusterLogger(cluster.Name)
+	logger.Printf("Processing cluster")
+
+	apps, err := c.processCluster(ctx, cluster, logger)
 	if err != nil {
-		return nil, fmt.Errorf("getting helm releases: %w", err)
+		logger.Printf("Error processing cluster: %v", err)
 	}
 
-	// Procesar releases y calcular actualizaciones
-	return c.processReleases(releases, availableCharts, cluster.Name), nil
+	return clusterResult{apps: apps, err: err}
 }
 
-// getAvailableCharts obtiene todos los charts disponibles del cluster
-func (c *Client) getAvailableCharts(cluster rancherClient.Cluster) ([]chart.Chart, error) {
-	chartFetcher := chart.NewFetcher(c.client, c.config.Verbose)
-	return chartFetcher.GetAllAvailableCharts(cluster)
+// clusterLogger crea un logger para los mensajes verbose de un cluster, prefijados con su nombre
+func (c *Client) clusterLogger(clusterName string) *log.Logger {
+	if !c.config.Verbose {
+		return log.New(io.Discard, "", 0)
+	}
+	return log.New(log.Writer(), "["+clusterName+"] ", log.Flags()|log.Lmsgprefix)
 }
 
-// getHelmReleases obtiene todos los releases de Helm del cluster
-func (c *Client) getHelmReleases(cluster rancherClient.Cluster) ([]*helm.Release, error) {
-	kubeConfigAction, err := c.client.Cluster.ActionGenerateKubeconfig(&cluster)
+// processCluster procesa un cluster individual
+func (c *Client) processCluster(ctx context.Context, cluster rancherClient.Cluster, logger *log.Logger) ([]HelmApp, error) {
+	config, err := c.restConfig(ctx, cluster)
 	if err != nil {
-		return nil, fmt.Errorf("getting kubeconfig: %w", err)
+		return nil, err
 	}
 
-	config, err := clientcmd.RESTConfigFromKubeConfig([]byte(kubeConfigAction.Config))
+	// Cargar charts disponibles una sola vez por cluster
+	availableCharts, err := c.getAvailableCharts(ctx, config, logger)
 	if err != nil {
-		return nil, fmt.Errorf("creating kube config: %w", err)
+		logger.Printf("Error loading available charts: %v", err)
+		availableCharts = []chart.Chart{} // Fallback
 	}
 
-	clientset, err := kubernetes.NewForConfig(config)
+	// Versión de Kubernetes para filtrar charts incompatibles
+	kubeVersion, err := c.kubeVersion(ctx, cluster, config)
 	if err != nil {
-		return nil, fmt.Errorf("creating clientset: %w", err)
+		logger.Printf("Error getting Kubernetes version: %v", err)
 	}
 
-	namespaces, err := clientset.CoreV1().Namespaces().List(context.Background(), metav1.ListOptions{})
+	// Obtener releases de Helm
+	releases, skipped, err := c.getHelmReleases(ctx, cluster.Name, config, logger)
 	if err != nil {
-		return nil, fmt.Errorf("listing namespaces: %w", err)
+		return nil, fmt.Errorf("getting helm releases: %w", err)
 	}
 
-	var releases []*helm.Release
-	seenReleases := make(map[string]*helm.Release)
-
-	for _, ns := range namespaces.Items {
-		secrets, err := clientset.CoreV1().Secrets(ns.Name).List(context.Background(), metav1.ListOptions{
-			LabelSelector: "owner=helm",
-		})
-		if err != nil {
-			continue
-		}
-
-		for _, secret := range secrets.Items {
-			if releaseData, exists := secret.Data["release"]; exists {
-				rel, err := helm.DecodeRelease(string(releaseData))
-				if err != nil {
-					continue
-				}
-
-				releaseKey := fmt.Sprintf("%s/%s", rel.Namespace, rel.Name)
-				if existingRel, exists := seenReleases[releaseKey]; !exists || rel.Version > existingRel.Revision {
-					helmRelease := helm.ExtractReleaseInfo(rel)
-					seenReleases[releaseKey] = helmRelease
-				}
-			}
-		}
-	}
+	// Procesar releases y calcular actualizaciones
+	apps := c.processReleases(ctx, releases, availableCharts, cluster.Name, kubeVersion, logger)
 
-	for _, rel := range seenReleases {
-		releases = append(releases, rel)
+	// Los namespaces sin permisos no impiden mostrar el resto de releases del cluster
+	if len(skipped) > 0 {
+		return apps, fmt.Errorf("skipped namespaces without permission to list helm releases: %s", strings.Join(skipped, ", "))
 	}
+	return apps, nil
+}
 
-	return releases, nil
+// getAvailableCharts obtiene todos los charts disponibles del cluster
+func (c *Client) getAvailableCharts(ctx context.Context, config *rest.Config, logger *log.Logger) ([]chart.Chart, error) {
+	return c.fetcher.GetAllAvailableCharts(ctx, config, logger)
 }
 
 // processReleases procesa releases y calcula información de actualizaciones
-func (c *Client) processReleases(releases []*helm.Release, availableCharts []chart.Chart, clusterName string) []HelmApp {
+func (c *Client) processReleases(ctx context.Context, releases []*helm.Release, availableCharts []chart.Chart, clusterName, kubeVersion string, logger *log.Logger) []HelmApp {
 	var apps []HelmApp
+	now := time.Now()
+
+	// Sin versión objetivo configurada se revisan las APIs contra la versión actual del cluster
+	targetKubeVersion := c.config.TargetKubeVersion
+	if targetKubeVersion == "" {
+		targetKubeVersion = kubeVersion
+	}
 
 	for _, rel := range releases {
-		latestVersion, repo := chart.FindLatestVersionBySource(rel.Sources, rel.ChartName, availableCharts)
+		app, availableChart, found := c.evaluateRelease(rel, availableCharts, clusterName, kubeVersion, now)
+		managed := isInternalChart(rel.ChartName)
+
+		if c.config.CheckValues && found && app.UpdateAvailable && version.IsNewer(app.CurrentVersion, app.TargetVersion) {
+			app.Values = c.checkValues(ctx, availableChart, app.TargetVersion, rel, logger)
+		}
 
-		// Verificar si es chart interno/managed
-		if isInternalChart(rel.ChartName) {
-			latestVersion = "managed"
+		if !managed {
+			app.Dependencies = checkDependencies(rel.Dependencies, availableCharts, c.channelPolicy.ChannelFor(clusterName, rel.ChartName))
 		}
 
-		app := HelmApp{
-			Release:         *rel,
-			CurrentVersion:  rel.Version,
-			LatestVersion:   latestVersion,
-			UpdateAvailable: version.IsNewer(rel.Version, latestVersion),
-			Cluster:         clusterName,
+		// Las imágenes se revisan también en charts gestionados: pueden sobrescribirse en los values
+		if c.images != nil {
+			app.Images = c.images.check(ctx, rel.Manifest, logger)
 		}
 
-		// Actualizar repo si se encontró
-		if repo != "unknown" {
-			app.Release.ChartRepo = repo
+		if targetKubeVersion != "" {
+			app.TargetKubeVersion = targetKubeVersion
+			app.APIs = checkAPIs(rel.Manifest, targetKubeVersion, logger)
 		}
 
 		apps = append(apps, app)
 
-		if c.config.Verbose {
-			log.Printf("Chart=%s, Repo=%s, Current=%s, Latest=%s, Update=%v",
-				rel.ChartName, app.Release.ChartRepo, rel.Version, latestVersion, app.UpdateAvailable)
-		}
+		logger.Printf("Chart=%s, Repo=%s, Current=%s, Latest=%s, Installable=%s, Allowed=%s, Channel=%s, Behind=%d, Update=%v, Type=%s, App=%s->%s, Health=%s",
+			rel.ChartName, app.Release.ChartRepo, app.CurrentVersion, app.LatestVersion, app.LatestInstallable, app.LatestAllowed, app.Channel, app.VersionsBehind, app.UpdateAvailable, app.UpdateType, app.CurrentAppVersion, app.LatestAppVersion, app.Health)
 	}
 
 	return apps
+}
+
+// evaluateRelease calcula la versión objetivo de un release (última instalable en el cluster y
+// permitida por la política) y su estado de actualización, sin las revisiones que consultan
+// registries o descargan charts. Retorna también el chart disponible del release, si se encontró.
+func (c *Client) evaluateRelease(rel *helm.Release, availableCharts []chart.Chart, clusterName, kubeVersion string, now time.Time) (HelmApp, chart.Chart, bool) {
+	// Las comparaciones parten de la versión desplegada, no de un intento fallido o pendiente
+	installed := rel.InstalledVersion()
+
+	channel := c.channelPolicy.ChannelFor(clusterName, rel.ChartName)
+	latestVersion, repo, producedBy := "unknown", "unknown", chart.ChannelStable
+
+	availableChart, found := chart.FindChart(rel.Sources, rel.ChartName, availableCharts)
+	if found {
+		repo = availableChart.Repo
+		// Las versiones deprecadas u ocultas nunca son objetivo de actualización
+		latestVersion, producedBy = availableChart.LatestForChannel(channel, chart.Available)
+	}
+
+	// Verificar si es chart interno/managed
+	managed := isInternalChart(rel.ChartName)
+	if managed {
+		latestVersion = "managed"
+	}
+
+	app := HelmApp{
+		Release:        *rel,
+		CurrentVersion: installed,
+		LatestVersion:  latestVersion,
+		Channel:        producedBy,
+		Cluster:        clusterName,
+		KubeVersion:    kubeVersion,
+		Deprecated:     found && !managed && availableChart.IsDeprecated(installed),
+		Health:         rel.Health(now),
+	}
+
+	// Solo se recomiendan versiones instalables en la versión de Kubernetes del cluster
+	// y en la versión del servidor Rancher
+	targetVersion := latestVersion
+	filters := []chart.VersionFilter{
+		chart.Available,
+		chart.KubeVersionFilter(kubeVersion),
+		chart.RancherVersionFilter(c.serverVersion),
+	}
+	if found && !managed && (kubeVersion != "" || c.serverVersion != "") {
+		app.LatestInstallable = "unknown"
+		if installable, ok := availableChart.Latest(channel, filters...); ok {
+			app.LatestInstallable = installable.Version
+		}
+		targetVersion = app.LatestInstallable
+
+		// Sin una versión más nueva compatible con Kubernetes el bloqueo es del cluster;
+		// si la hay, la descartó la versión del servidor Rancher
+		if version.IsNewer(installed, latestVersion) && !version.IsNewer(installed, app.LatestInstallable) {
+			app.BlockedBy = BlockedByRancher
+			if compatible, ok := availableChart.Latest(channel, chart.Available, chart.KubeVersionFilter(kubeVersion)); !ok || !version.IsNewer(installed, compatible.Version) {
+				app.BlockedBy = BlockedByKubernetes
+			}
+		}
+	}
+
+	// Una regla de la política restringe además las versiones objetivo
+	var rule *policy.Rule
+	if matched, pinned := c.policy.Match(clusterName, rel.Namespace, rel.Name, rel.ChartName); pinned && !managed {
+		rule = matched
+		app.PolicyConstraint = rule.Constraint
+		app.PolicyCompliant = rule.Allows(installed)
+		app.LatestAllowed = "unknown"
+		filters = append(filters, func(v chart.ChartVersion) bool { return rule.Allows(v.Version) })
+
+		if found {
+			if allowed, ok := availableChart.Latest(channel, filters...); ok {
+				app.LatestAllowed = allowed.Version
+			}
+		}
+		targetVersion = app.LatestAllowed
+	}
+
+	// Muchos charts cambian de versión sin cambiar la aplicación y viceversa, así que se
+	// compara también el appVersion de la versión objetivo
+	app.CurrentAppVersion = rel.InstalledAppVersion()
+	if found {
+		if target, ok := availableChart.FindVersion(targetVersion); ok {
+			app.LatestAppVersion = target.AppVersion
+		}
+	}
+	app.AppUpdateType = version.Classify(app.CurrentAppVersion, app.LatestAppVersion)
+	appVersionNewer := app.LatestAppVersion != "" && version.IsNewer(app.CurrentAppVersion, app.LatestAppVersion)
+
+	app.TargetVersion = targetVersion
+	chartNewer := version.IsNewer(installed, targetVersion)
+	app.UpdateAvailable = rule.Triggers(chartNewer, appVersionNewer)
+	app.ChartOnlyUpdate = chartNewer && !app.UpdateAvailable
+
+	if found && app.UpdateAvailable {
+		app.UpdateType = version.Classify(installed, targetVersion)
+		// Solo se cuentan las versiones que podrían instalarse, con los mismos filtros que el objetivo
+		app.VersionsBehind = availableChart.VersionsBehind(installed, channel, filters...)
+		app.IntermediateVersions = availableChart.VersionsBetween(installed, targetVersion, channel, filters...)
+		if v, ok := availableChart.LatestInMinor(installed, channel, filters...); ok {
+			app.LatestInMinor = v.Version
+		}
+		if v, ok := availableChart.LatestInMajor(installed, channel, filters...); ok {
+			app.LatestInMajor = v.Version
+		}
+	}
+
+	// Actualizar repo si se encontró
+	if repo != "unknown" {
+		app.Release.ChartRepo = repo
+	}
+
+	return app, availableChart, found
 }
\ No newline at end of file
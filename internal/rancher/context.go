@@ -0,0 +1,37 @@
+package rancher
+
+import (
+	"context"
+	"time"
+)
+
+// withTimeout aplica un timeout al contexto si es mayor que cero
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// callWithContext ejecuta una llamada que no acepta contexto (como las del cliente norman de
+// Rancher) y deja de esperarla cuando el contexto se cancela o vence
+func callWithContext[T any](ctx context.Context, fn func() (T, error)) (T, error) {
+	type result struct {
+		value T
+		err   error
+	}
+
+	done := make(chan result, 1)
+	go func() {
+		value, err := fn()
+		done <- result{value: value, err: err}
+	}()
+
+	select {
+	case r := <-done:
+		return r.value, r.err
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
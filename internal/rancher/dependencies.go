@@ -0,0 +1,50 @@
+package rancher
+
+import (
+	"strings"
+
+	"github.com/start-codex/rke-update-checker/internal/chart"
+	"github.com/start-codex/rke-update-checker/internal/helm"
+	"github.com/start-codex/rke-update-checker/internal/version"
+)
+
+// DependencyUpdate representa el estado de actualización de una dependencia (subchart) de un release
+type DependencyUpdate struct {
+	Dependency      helm.Dependency
+	LatestVersion   string
+	Repo            string
+	UpdateAvailable bool
+	UpdateType      version.Change
+}
+
+// checkDependencies compara la versión de cada dependencia del release con la última versión
+// publicada en los repositorios, dentro del mismo canal que el chart padre
+func checkDependencies(dependencies []helm.Dependency, availableCharts []chart.Chart, channel chart.Channel) []DependencyUpdate {
+	var updates []DependencyUpdate
+
+	for _, dependency := range dependencies {
+		update := DependencyUpdate{
+			Dependency:    dependency,
+			LatestVersion: "unknown",
+			Repo:          "unknown",
+		}
+
+		// Las dependencias locales (file://) forman parte del propio chart
+		if strings.HasPrefix(dependency.Repository, "file://") {
+			update.LatestVersion = "local"
+			updates = append(updates, update)
+			continue
+		}
+
+		if available, found := chart.FindDependency(availableCharts, dependency.Name, dependency.Repository); found {
+			update.Repo = available.Repo
+			update.LatestVersion, _ = available.LatestForChannel(channel, chart.Available)
+			update.UpdateType = version.Classify(dependency.Version, update.LatestVersion)
+			update.UpdateAvailable = update.UpdateType != version.ChangeNone
+		}
+
+		updates = append(updates, update)
+	}
+
+	return updates
+}
@@ -0,0 +1,69 @@
+package rancher
+
+import (
+	"context"
+	"fmt"
+
+	rancherClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+
+	"github.com/start-codex/rke-update-checker/internal/helm"
+)
+
+// FindCluster busca un cluster por nombre o por ID
+func (c *Client) FindCluster(ctx context.Context, name string) (rancherClient.Cluster, error) {
+	clusters, err := c.ListClusters(ctx)
+	if err != nil {
+		return rancherClient.Cluster{}, err
+	}
+
+	for _, cluster := range clusters {
+		if cluster.Name == name || cluster.ID == name {
+			return cluster, nil
+		}
+	}
+
+	return rancherClient.Cluster{}, fmt.Errorf("cluster %q not found", name)
+}
+
+// ReleaseHistory decodifica todas las revisiones de un release y las retorna ordenadas de la
+// más antigua a la más reciente
+func (c *Client) ReleaseHistory(ctx context.Context, cluster rancherClient.Cluster, namespace, name string) ([]helm.Revision, error) {
+	config, err := c.restConfig(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := withTimeout(ctx, c.config.SecretsTimeout)
+	defer cancel()
+
+	records, _, err := c.listReleaseRecords(ctx, cluster.Name, config, c.clusterLogger(cluster.Name))
+	if err != nil {
+		return nil, fmt.Errorf("getting helm releases: %w", err)
+	}
+
+	var revisions []helm.Revision
+	for _, record := range records {
+		// Los releases de Helm 2 se guardan en el namespace de Tiller, por lo que su
+		// namespace real solo se conoce después de decodificarlos
+		if record.Name != name || (record.Namespace != namespace && record.Storage != helm.StorageTiller) {
+			continue
+		}
+
+		rel, err := record.Decode()
+		if err != nil {
+			return nil, fmt.Errorf("decoding %s: %w", record.Key, err)
+		}
+		if rel.Namespace != namespace {
+			continue
+		}
+
+		revisions = append(revisions, helm.NewRevision(rel, record.Storage))
+	}
+
+	if len(revisions) == 0 {
+		return nil, fmt.Errorf("release %s/%s not found", namespace, name)
+	}
+
+	helm.SortRevisions(revisions)
+	return revisions, nil
+}
@@ -0,0 +1,195 @@
+package rancher
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/start-codex/rke-update-checker/internal/chart"
+	"github.com/start-codex/rke-update-checker/internal/manifest"
+	"github.com/start-codex/rke-update-checker/internal/oci"
+	"github.com/start-codex/rke-update-checker/internal/version"
+)
+
+// ImageUpdate representa el estado de actualización de una imagen de contenedor de un release
+type ImageUpdate struct {
+	Image manifest.ContainerImage
+	// Repository es el repositorio normalizado de la imagen (ej. docker.io/library/nginx)
+	Repository      string
+	Tag             string
+	LatestTag       string
+	UpdateAvailable bool
+	UpdateType      version.Change
+	// AuthRequired indica que el registry rechazó la consulta de tags por falta de credenciales
+	AuthRequired bool
+}
+
+// imageChecker compara los tags de las imágenes con los publicados en sus registries.
+// La lista de tags de cada repositorio se consulta una sola vez por ejecución.
+type imageChecker struct {
+	repositories []chart.Repository
+	timeout      time.Duration
+
+	mu      sync.Mutex
+	clients map[string]*oci.Client
+	tags    map[string]*tagEntry
+}
+
+// tagEntry contiene la lista de tags de un repositorio; su mutex serializa la consulta
+// y done indica que el resultado ya es definitivo
+type tagEntry struct {
+	mu   sync.Mutex
+	done bool
+	tags []string
+	err  error
+}
+
+// newImageChecker crea un verificador de imágenes que reutiliza las credenciales de los
+// repositorios OCI configurados en el mismo registry y accede al resto de forma anónima
+func newImageChecker(repositories []chart.Repository, timeout time.Duration) *imageChecker {
+	return &imageChecker{
+		repositories: repositories,
+		timeout:      timeout,
+		clients:      make(map[string]*oci.Client),
+		tags:         make(map[string]*tagEntry),
+	}
+}
+
+// check extrae las imágenes del manifiesto de un release y compara cada tag con el más
+// reciente de la misma variante publicado en su registry
+func (ic *imageChecker) check(ctx context.Context, releaseManifest string, logger *log.Logger) []ImageUpdate {
+	objects, err := manifest.Parse(releaseManifest)
+	if err != nil {
+		// Los documentos inválidos se omiten y el resto del manifiesto se revisa igual
+		logger.Printf("Error parsing manifest: %v", err)
+	}
+
+	var updates []ImageUpdate
+	seen := make(map[string]bool)
+
+	for _, containerImage := range manifest.Images(objects) {
+		// Una misma imagen usada en varios contenedores se reporta una sola vez
+		if seen[containerImage.Image] {
+			continue
+		}
+		seen[containerImage.Image] = true
+
+		update := ImageUpdate{Image: containerImage, LatestTag: "unknown"}
+
+		image, err := oci.ParseImage(containerImage.Image)
+		if err != nil {
+			logger.Printf("Error parsing image %s: %v", containerImage.Image, err)
+			updates = append(updates, update)
+			continue
+		}
+		update.Repository = image.Reference.String()
+		update.Tag = image.Tag
+
+		// Las imágenes fijadas solo por digest o con tags sin versión (latest, stable) no se comparan
+		if _, err := version.Parse(image.Tag); err != nil {
+			update.LatestTag = "-"
+			updates = append(updates, update)
+			continue
+		}
+
+		tags, err := ic.listTags(ctx, image.Reference)
+		if errors.Is(err, oci.ErrUnauthorized) {
+			// Un registry privado sin credenciales configuradas no es un error de la revisión
+			logger.Printf("Registry requires authentication to list tags of %s: %v", update.Repository, err)
+			update.AuthRequired = true
+			updates = append(updates, update)
+			continue
+		}
+		if err != nil {
+			logger.Printf("Error listing tags of %s: %v", update.Repository, err)
+			updates = append(updates, update)
+			continue
+		}
+
+		update.LatestTag = latestTag(image.Tag, tags)
+		update.UpdateType = version.Classify(image.Tag, update.LatestTag)
+		update.UpdateAvailable = update.UpdateType != version.ChangeNone
+		updates = append(updates, update)
+	}
+
+	return updates
+}
+
+// listTags obtiene los tags de un repositorio, compartiendo el resultado entre clusters
+func (ic *imageChecker) listTags(ctx context.Context, ref oci.Reference) ([]string, error) {
+	ic.mu.Lock()
+	entry, exists := ic.tags[ref.String()]
+	if !exists {
+		entry = &tagEntry{}
+		ic.tags[ref.String()] = entry
+	}
+	client := ic.client(ref.Registry)
+	ic.mu.Unlock()
+
+	entry.mu.Lock()
+	defer entry.mu.Unlock()
+	if entry.done {
+		return entry.tags, entry.err
+	}
+
+	ctx, cancel := withTimeout(ctx, ic.timeout)
+	defer cancel()
+	tags, err := client.ListTags(ctx, ref)
+
+	// Una cancelación o un timeout son propios del cluster que hizo la consulta, así que no
+	// se guardan y el siguiente cluster vuelve a intentarlo
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return nil, err
+	}
+
+	entry.tags, entry.err, entry.done = tags, err, true
+	return tags, err
+}
+
+// client retorna el cliente de un registry; el llamador debe tener tomado ic.mu
+func (ic *imageChecker) client(registry string) *oci.Client {
+	client, exists := ic.clients[registry]
+	if !exists {
+		opts, _ := chart.RegistryOptions(ic.repositories, registry)
+		client = oci.NewClient(opts)
+		ic.clients[registry] = client
+	}
+	return client
+}
+
+// latestTag retorna el tag más reciente con la misma forma que el actual, de modo que una
+// imagen 1.25.3-alpine solo se compare con otras variantes alpine y no con 1.27.0 o 1.27.0-rc.1
+func latestTag(current string, tags []string) string {
+	shape := tagShape(current)
+
+	latest := current
+	for _, tag := range tags {
+		if tagShape(tag) == shape && version.Compare(tag, latest) > 0 {
+			latest = tag
+		}
+	}
+	return latest
+}
+
+// tagShape resume la forma de un tag: prefijo v, cantidad de componentes numéricos y variante
+// (el sufijo sin dígitos, ej. "1.25.3-debian-11-r2" -> "debian--r")
+func tagShape(tag string) string {
+	core, variant, _ := strings.Cut(tag, "-")
+
+	prefix := ""
+	if strings.HasPrefix(core, "v") {
+		prefix = "v"
+	}
+
+	variant = strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return -1
+		}
+		return r
+	}, variant)
+
+	return prefix + strings.Repeat("n.", strings.Count(core, ".")+1) + "-" + variant
+}
@@ -0,0 +1,58 @@
+package rancher
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	rancherClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+	"k8s.io/apimachinery/pkg/version"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// restConfig genera la configuración de acceso al cluster a partir del kubeconfig de Rancher
+func (c *Client) restConfig(ctx context.Context, cluster rancherClient.Cluster) (*rest.Config, error) {
+	ctx, cancel := withTimeout(ctx, c.config.KubeconfigTimeout)
+	defer cancel()
+
+	kubeConfigAction, err := callWithContext(ctx, func() (*rancherClient.GenerateKubeConfigOutput, error) {
+		return c.client.Cluster.ActionGenerateKubeconfig(&cluster)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("getting kubeconfig: %w", err)
+	}
+
+	config, err := clientcmd.RESTConfigFromKubeConfig([]byte(kubeConfigAction.Config))
+	if err != nil {
+		return nil, fmt.Errorf("creating kube config: %w", err)
+	}
+
+	return config, nil
+}
+
+// kubeVersion obtiene la versión de Kubernetes del cluster, primero desde el objeto
+// Cluster de Rancher y si no está disponible desde la API de discovery
+func (c *Client) kubeVersion(ctx context.Context, cluster rancherClient.Cluster, config *rest.Config) (string, error) {
+	if cluster.Version != nil && cluster.Version.GitVersion != "" {
+		return cluster.Version.GitVersion, nil
+	}
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(config)
+	if err != nil {
+		return "", fmt.Errorf("creating discovery client: %w", err)
+	}
+
+	body, err := discoveryClient.RESTClient().Get().AbsPath("/version").Do(ctx).Raw()
+	if err != nil {
+		return "", fmt.Errorf("getting server version: %w", err)
+	}
+
+	var info version.Info
+	if err := json.Unmarshal(body, &info); err != nil {
+		return "", fmt.Errorf("decoding server version: %w", err)
+	}
+
+	return info.GitVersion, nil
+}
@@ -0,0 +1,121 @@
+package rancher
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	rancherClient "github.com/rancher/rancher/pkg/client/generated/management/v3"
+
+	"github.com/start-codex/rke-update-checker/internal/chart"
+	"github.com/start-codex/rke-update-checker/internal/helm"
+	"github.com/start-codex/rke-update-checker/internal/manifest"
+)
+
+// Plan es la vista previa de la actualización de un release: los objetos del manifiesto actual
+// que cambiarían al renderizar la versión objetivo del chart con los values del release
+type Plan struct {
+	Cluster string
+	Release helm.Release
+
+	CurrentVersion    string
+	TargetVersion     string
+	CurrentAppVersion string
+	TargetAppVersion  string
+
+	Changes []manifest.Change
+	// Cantidad de objetos que no cambian
+	Unchanged int
+	// Errores de validación de los values del release contra el values.schema.json de la versión objetivo
+	SchemaErrors []string
+}
+
+// PlanUpgrade renderiza localmente la actualización de un release y la compara con su manifiesto
+// desplegado, sin modificar el cluster. Sin targetVersion se usa la versión objetivo que
+// calcula la revisión de actualizaciones (última instalable y permitida por la política).
+func (c *Client) PlanUpgrade(ctx context.Context, cluster rancherClient.Cluster, namespace, name, targetVersion string) (*Plan, error) {
+	logger := c.clusterLogger(cluster.Name)
+
+	config, err := c.restConfig(ctx, cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	availableCharts, err := c.getAvailableCharts(ctx, config, logger)
+	if err != nil {
+		return nil, fmt.Errorf("loading available charts: %w", err)
+	}
+
+	kubeVersion, err := c.kubeVersion(ctx, cluster, config)
+	if err != nil {
+		logger.Printf("Error getting Kubernetes version: %v", err)
+	}
+
+	releases, _, err := c.getHelmReleases(ctx, cluster.Name, config, logger)
+	if err != nil {
+		return nil, fmt.Errorf("getting helm releases: %w", err)
+	}
+
+	var rel *helm.Release
+	for _, candidate := range releases {
+		if candidate.Namespace == namespace && candidate.Name == name {
+			rel = candidate
+			break
+		}
+	}
+	if rel == nil {
+		return nil, fmt.Errorf("release %s/%s not found", namespace, name)
+	}
+
+	availableChart, found := chart.FindChart(rel.Sources, rel.ChartName, availableCharts)
+	if !found {
+		return nil, fmt.Errorf("chart %s not found in the available repositories", rel.ChartName)
+	}
+
+	if targetVersion == "" {
+		// Solo se calcula el objetivo, sin las revisiones de imágenes, values y APIs del reporte
+		c.loadServerVersion(ctx)
+		app, _, _ := c.evaluateRelease(rel, availableCharts, cluster.Name, kubeVersion, time.Now())
+		targetVersion = app.TargetVersion
+	}
+	target, ok := availableChart.FindVersion(targetVersion)
+	if !ok {
+		return nil, fmt.Errorf("no target version %q for chart %s", targetVersion, rel.ChartName)
+	}
+
+	targetChart, err := c.fetcher.DownloadChart(ctx, availableChart, target.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	rendered, err := helm.Render(targetChart, rel, kubeVersion)
+	if err != nil {
+		return nil, fmt.Errorf("rendering %s %s: %w", rel.ChartName, target.Version, err)
+	}
+
+	currentObjects, err := manifest.Parse(rel.Manifest)
+	if err != nil {
+		return nil, fmt.Errorf("parsing current manifest: %w", err)
+	}
+	plannedObjects, err := manifest.Parse(rendered)
+	if err != nil {
+		return nil, fmt.Errorf("parsing rendered manifest: %w", err)
+	}
+
+	changes, unchanged, err := manifest.Diff(currentObjects, plannedObjects)
+	if err != nil {
+		return nil, fmt.Errorf("comparing manifests: %w", err)
+	}
+
+	return &Plan{
+		Cluster:           cluster.Name,
+		Release:           *rel,
+		CurrentVersion:    rel.InstalledVersion(),
+		TargetVersion:     target.Version,
+		CurrentAppVersion: rel.InstalledAppVersion(),
+		TargetAppVersion:  targetChart.Metadata.AppVersion,
+		Changes:           changes,
+		Unchanged:         unchanged,
+		SchemaErrors:      helm.ValidateValues(targetChart, rel.Config),
+	}, nil
+}
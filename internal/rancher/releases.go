@@ -0,0 +1,311 @@
+package rancher
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/start-codex/rke-update-checker/internal/helm"
+)
+
+// storagePageSize es la cantidad de objetos pedidos por página al API server
+const storagePageSize = 500
+
+// helmOwnerSelector selecciona los Secrets y ConfigMaps en los que Helm 3 guarda sus releases
+const helmOwnerSelector = "owner=helm"
+
+// tillerNamespace es el namespace por defecto en el que Tiller guarda sus ConfigMaps
+const tillerNamespace = "kube-system"
+
+// listPageFunc lista una página de objetos de un namespace (o de todo el cluster con
+// NamespaceAll) y retorna el token para continuar con la siguiente
+type listPageFunc[T any] func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]T, string, error)
+
+// getHelmReleases obtiene todos los releases de Helm del cluster a partir de las revisiones de
+// listReleaseRecords. Retorna también los namespaces omitidos por falta de permisos.
+func (c *Client) getHelmReleases(ctx context.Context, clusterName string, config *rest.Config, logger *log.Logger) ([]*helm.Release, []string, error) {
+	ctx, cancel := withTimeout(ctx, c.config.SecretsTimeout)
+	defer cancel()
+
+	records, skipped, err := c.listReleaseRecords(ctx, clusterName, config, logger)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Las etiquetas identifican la última revisión de cada release, así que solo se decodifica esa
+	var releases []*helm.Release
+	for _, revisions := range helm.SelectLatest(records) {
+		rel, err := revisions.Latest.Decode()
+		if err != nil {
+			continue
+		}
+
+		helmRelease := helm.ExtractReleaseInfo(rel)
+		helmRelease.Storage = revisions.Latest.Storage
+		if revisions.Latest.ModifiedAt > 0 {
+			helmRelease.StatusSince = time.Unix(revisions.Latest.ModifiedAt, 0)
+		}
+
+		// Si la última revisión no es la desplegada, se decodifica también la desplegada
+		// para conocer la versión de chart que realmente está corriendo
+		if deployed := revisions.Deployed; deployed != nil && deployed.Key != revisions.Latest.Key {
+			if deployedRel, err := deployed.Decode(); err == nil {
+				helmRelease.DeployedRevision = deployed.Revision
+				helmRelease.DeployedVersion = deployedRel.Chart.Metadata.Version
+				helmRelease.DeployedAppVersion = deployedRel.Chart.Metadata.AppVersion
+				helmRelease.Dependencies = helm.ExtractDependencies(deployedRel.Chart)
+				helmRelease.Manifest = deployedRel.Manifest
+				helmRelease.ChartValues = deployedRel.Chart.Values
+				helmRelease.Config = deployedRel.Config
+			}
+		}
+
+		releases = append(releases, helmRelease)
+	}
+
+	// Los releases de Helm 2 se guardan en el namespace de Tiller, no en el del release
+	sort.SliceStable(releases, func(i, j int) bool {
+		if releases[i].Namespace != releases[j].Namespace {
+			return releases[i].Namespace < releases[j].Namespace
+		}
+		return releases[i].Name < releases[j].Name
+	})
+
+	return releases, skipped, nil
+}
+
+// listReleaseRecords lista sin decodificar todas las revisiones de releases del cluster en los
+// drivers secret y configmap, en los ConfigMaps de Tiller y, si está configurado, en el driver SQL.
+// Retorna también los namespaces en los que no hubo permiso para listar Secrets; las fuentes
+// opcionales omitidas por falta de permisos solo se informan en el log verbose.
+func (c *Client) listReleaseRecords(ctx context.Context, clusterName string, config *rest.Config, logger *log.Logger) ([]helm.Record, []string, error) {
+	clientset, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating clientset: %w", err)
+	}
+
+	secretRecords, skippedSecrets, err := listHelmRecords(ctx, clientset, "secrets", helmOwnerSelector, helmSecretsPage(clientset), secretRecord)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Los drivers configmap y Tiller son poco habituales: sin permiso para listarlos se omiten
+	configMapRecords, skippedConfigMaps, err := listOptionalHelmRecords(ctx, "configmaps", helmOwnerSelector, helmConfigMapsPage(clientset), configMapRecord)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Releases de Helm 2 que Tiller dejó en ConfigMaps; sin permiso a nivel de cluster se
+	// buscan en su namespace por defecto
+	tillerRecords, skippedTiller, err := listOptionalHelmRecords(ctx, "tiller configmaps", helm.TillerOwnerSelector, helmConfigMapsPage(clientset), tillerRecord, tillerNamespace)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if optional := append(skippedConfigMaps, skippedTiller...); len(optional) > 0 {
+		logger.Printf("Skipped optional helm storage without permission to list: %s", strings.Join(optional, ", "))
+	}
+
+	records := append(append(secretRecords, configMapRecords...), tillerRecords...)
+
+	if source, ok := c.sqlConfig.For(clusterName); ok {
+		sqlRecords, err := source.ListRecords(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("listing sql releases: %w", err)
+		}
+		records = append(records, sqlRecords...)
+	}
+
+	return records, skippedSecrets, nil
+}
+
+// listHelmRecords lista los objetos de almacenamiento de Helm de todo el cluster con una única
+// consulta paginada. Si no hay permiso para listarlos a nivel de cluster, recurre a listarlos
+// namespace por namespace y retorna los namespaces en los que el permiso también fue denegado.
+func listHelmRecords[T any](ctx context.Context, clientset kubernetes.Interface, resource, selector string, list listPageFunc[T], toRecord func(T) (helm.Record, bool)) ([]helm.Record, []string, error) {
+	objects, err := listAllPages(ctx, list, metav1.NamespaceAll, selector)
+	var skipped []string
+
+	if apierrors.IsForbidden(err) {
+		objects, skipped, err = listByNamespace(ctx, clientset, resource, selector, list)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing %s: %w", resource, err)
+	}
+
+	return toRecords(objects, toRecord), skipped, nil
+}
+
+// listOptionalHelmRecords lista los objetos de almacenamiento de Helm de todo el cluster como
+// listHelmRecords, pero sin recorrer todos los namespaces: si no hay permiso a nivel de cluster
+// solo se consultan los namespaces indicados, y sin ellos la fuente completa se reporta una sola
+// vez como omitida
+func listOptionalHelmRecords[T any](ctx context.Context, resource, selector string, list listPageFunc[T], toRecord func(T) (helm.Record, bool), namespaces ...string) ([]helm.Record, []string, error) {
+	objects, err := listAllPages(ctx, list, metav1.NamespaceAll, selector)
+	if apierrors.IsForbidden(err) {
+		if len(namespaces) == 0 {
+			return nil, []string{fmt.Sprintf("all namespaces (%s)", resource)}, nil
+		}
+
+		var skipped []string
+		objects = nil
+		for _, namespace := range namespaces {
+			nsObjects, err := listAllPages(ctx, list, namespace, selector)
+			if apierrors.IsForbidden(err) {
+				skipped = append(skipped, fmt.Sprintf("%s (%s)", namespace, resource))
+				continue
+			}
+			if err != nil {
+				return nil, nil, fmt.Errorf("listing %s in %s: %w", resource, namespace, err)
+			}
+			objects = append(objects, nsObjects...)
+		}
+		return toRecords(objects, toRecord), skipped, nil
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing %s: %w", resource, err)
+	}
+
+	return toRecords(objects, toRecord), nil, nil
+}
+
+// toRecords convierte los objetos de almacenamiento en registros, descartando los que no contienen un release
+func toRecords[T any](objects []T, toRecord func(T) (helm.Record, bool)) []helm.Record {
+	var records []helm.Record
+	for _, object := range objects {
+		if record, ok := toRecord(object); ok {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+// listByNamespace lista los objetos namespace por namespace, omitiendo aquellos sin permiso.
+// Cualquier otro error (timeouts, errores del API server) se acumula y se retorna, para que
+// los releases de esos namespaces no desaparezcan del reporte sin aviso.
+func listByNamespace[T any](ctx context.Context, clientset kubernetes.Interface, resource, selector string, list listPageFunc[T]) ([]T, []string, error) {
+	namespaces, err := clientset.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("listing namespaces: %w", err)
+	}
+
+	var objects []T
+	var skipped []string
+	var errs []error
+	for _, ns := range namespaces.Items {
+		nsObjects, err := listAllPages(ctx, list, ns.Name, selector)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, nil, ctx.Err()
+			}
+			if apierrors.IsForbidden(err) {
+				skipped = append(skipped, fmt.Sprintf("%s (%s)", ns.Name, resource))
+			} else {
+				errs = append(errs, fmt.Errorf("namespace %s: %w", ns.Name, err))
+			}
+			continue
+		}
+		objects = append(objects, nsObjects...)
+	}
+
+	if len(errs) > 0 {
+		return nil, nil, errors.Join(errs...)
+	}
+	return objects, skipped, nil
+}
+
+// listAllPages recorre todas las páginas de un listado filtrado por el selector de etiquetas
+func listAllPages[T any](ctx context.Context, list listPageFunc[T], namespace, selector string) ([]T, error) {
+	opts := metav1.ListOptions{
+		LabelSelector: selector,
+		Limit:         storagePageSize,
+	}
+
+	var objects []T
+	for {
+		page, next, err := list(ctx, namespace, opts)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, page...)
+
+		if next == "" {
+			return objects, nil
+		}
+		opts.Continue = next
+	}
+}
+
+// helmSecretsPage lista páginas de Secrets (driver secret, el de defecto en Helm 3)
+func helmSecretsPage(clientset kubernetes.Interface) listPageFunc[corev1.Secret] {
+	return func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.Secret, string, error) {
+		list, err := clientset.CoreV1().Secrets(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, "", err
+		}
+		return list.Items, list.Continue, nil
+	}
+}
+
+// helmConfigMapsPage lista páginas de ConfigMaps (driver configmap, HELM_DRIVER=configmap)
+func helmConfigMapsPage(clientset kubernetes.Interface) listPageFunc[corev1.ConfigMap] {
+	return func(ctx context.Context, namespace string, opts metav1.ListOptions) ([]corev1.ConfigMap, string, error) {
+		list, err := clientset.CoreV1().ConfigMaps(namespace).List(ctx, opts)
+		if err != nil {
+			return nil, "", err
+		}
+		return list.Items, list.Continue, nil
+	}
+}
+
+// secretRecord crea el registro de la revisión guardada en un Secret
+func secretRecord(secret corev1.Secret) (helm.Record, bool) {
+	releaseData, exists := secret.Data["release"]
+	if !exists {
+		return helm.Record{}, false
+	}
+
+	record, err := helm.NewRecord(secret.Name, secret.Namespace, secret.Labels, string(releaseData), helm.StorageSecret)
+	if err != nil {
+		return helm.Record{}, false
+	}
+	return record, true
+}
+
+// configMapRecord crea el registro de la revisión guardada en un ConfigMap
+func configMapRecord(configMap corev1.ConfigMap) (helm.Record, bool) {
+	releaseData, exists := configMap.Data["release"]
+	if !exists {
+		return helm.Record{}, false
+	}
+
+	record, err := helm.NewRecord(configMap.Name, configMap.Namespace, configMap.Labels, releaseData, helm.StorageConfigMap)
+	if err != nil {
+		return helm.Record{}, false
+	}
+	return record, true
+}
+
+// tillerRecord crea el registro de una revisión de Helm 2 guardada por Tiller en un ConfigMap
+func tillerRecord(configMap corev1.ConfigMap) (helm.Record, bool) {
+	releaseData, exists := configMap.Data["release"]
+	if !exists {
+		return helm.Record{}, false
+	}
+
+	record, err := helm.NewTillerRecord(configMap.Name, configMap.Namespace, configMap.Labels, releaseData)
+	if err != nil {
+		return helm.Record{}, false
+	}
+	return record, true
+}
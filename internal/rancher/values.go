@@ -0,0 +1,23 @@
+package rancher
+
+import (
+	"context"
+	"log"
+
+	"github.com/start-codex/rke-update-checker/internal/chart"
+	"github.com/start-codex/rke-update-checker/internal/helm"
+)
+
+// checkValues descarga la versión objetivo del chart y compara sus values por defecto y su
+// values.schema.json con los del chart instalado y los values del release
+func (c *Client) checkValues(ctx context.Context, availableChart chart.Chart, targetVersion string, rel *helm.Release, logger *log.Logger) *helm.ValuesDrift {
+	target, err := c.fetcher.DownloadChart(ctx, availableChart, targetVersion)
+	if err != nil {
+		logger.Printf("Error downloading chart %s %s: %v", availableChart.Chart, targetVersion, err)
+		return nil
+	}
+
+	drift := helm.CompareValues(rel.ChartValues, target.Values, rel.Config)
+	drift.SchemaErrors = helm.ValidateValues(target, rel.Config)
+	return &drift
+}
@@ -1,73 +1,283 @@
 package version
 
 import (
-	"regexp"
+	"fmt"
 	"strconv"
 	"strings"
 )
 
+// Version representa una versión semántica según SemVer 2.0
+type Version struct {
+	Major      uint64
+	Minor      uint64
+	Patch      uint64
+	PreRelease []string
+	Build      string
+}
+
+// Parse interpreta una string como versión SemVer 2.0.
+// Por compatibilidad con los charts acepta el prefijo v/V y omite minor/patch ausentes (1.2 == 1.2.0).
+func Parse(s string) (Version, error) {
+	var v Version
+
+	clean := strings.TrimSpace(s)
+	clean = strings.TrimPrefix(strings.TrimPrefix(clean, "v"), "V")
+	if clean == "" {
+		return v, fmt.Errorf("empty version")
+	}
+
+	// Build metadata: todo lo que sigue al primer '+'
+	if i := strings.Index(clean, "+"); i >= 0 {
+		v.Build = clean[i+1:]
+		clean = clean[:i]
+		if err := validateIdentifiers(v.Build, false); err != nil {
+			return Version{}, fmt.Errorf("invalid build metadata in %q: %w", s, err)
+		}
+	}
+
+	// Pre-release: todo lo que sigue al primer '-'
+	if i := strings.Index(clean, "-"); i >= 0 {
+		preRelease := clean[i+1:]
+		clean = clean[:i]
+		if err := validateIdentifiers(preRelease, true); err != nil {
+			return Version{}, fmt.Errorf("invalid pre-release in %q: %w", s, err)
+		}
+		v.PreRelease = strings.Split(preRelease, ".")
+	}
+
+	parts := strings.Split(clean, ".")
+	if len(parts) > 3 {
+		return Version{}, fmt.Errorf("invalid version %q: too many components", s)
+	}
+
+	nums := make([]uint64, 3)
+	for i, part := range parts {
+		if part == "" || !isNumeric(part) {
+			return Version{}, fmt.Errorf("invalid version %q: component %q is not numeric", s, part)
+		}
+		n, err := strconv.ParseUint(part, 10, 64)
+		if err != nil {
+			return Version{}, fmt.Errorf("invalid version %q: %w", s, err)
+		}
+		nums[i] = n
+	}
+
+	v.Major, v.Minor, v.Patch = nums[0], nums[1], nums[2]
+	return v, nil
+}
+
+// IsPreRelease indica si la versión tiene identificadores de pre-release
+func (v Version) IsPreRelease() bool {
+	return len(v.PreRelease) > 0
+}
+
+// String retorna la representación canónica de la versión
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if len(v.PreRelease) > 0 {
+		s += "-" + strings.Join(v.PreRelease, ".")
+	}
+	if v.Build != "" {
+		s += "+" + v.Build
+	}
+	return s
+}
+
+// Compare compara dos versiones parseadas según la precedencia de SemVer 2.0.
+// La build metadata no participa en la precedencia.
+func (v Version) Compare(o Version) int {
+	if c := compareUint(v.Major, o.Major); c != 0 {
+		return c
+	}
+	if c := compareUint(v.Minor, o.Minor); c != 0 {
+		return c
+	}
+	if c := compareUint(v.Patch, o.Patch); c != 0 {
+		return c
+	}
+	return comparePreRelease(v.PreRelease, o.PreRelease)
+}
+
+// Change clasifica el tipo de salto entre dos versiones
+type Change string
+
+const (
+	ChangeNone       Change = ""
+	ChangeMajor      Change = "major"
+	ChangeMinor      Change = "minor"
+	ChangePatch      Change = "patch"
+	ChangePreRelease Change = "prerelease"
+)
+
+// Classify retorna el tipo de actualización que supone pasar de currentVersion a newVersion.
+// Retorna ChangeNone si newVersion no es más nueva o alguna de las versiones no es válida.
+func Classify(currentVersion, newVersion string) Change {
+	current, err := Parse(currentVersion)
+	if err != nil {
+		return ChangeNone
+	}
+	newer, err := Parse(newVersion)
+	if err != nil || newer.Compare(current) <= 0 {
+		return ChangeNone
+	}
+
+	switch {
+	case newer.Major != current.Major:
+		return ChangeMajor
+	case newer.Minor != current.Minor:
+		return ChangeMinor
+	case newer.Patch != current.Patch:
+		return ChangePatch
+	}
+
+	// Mismo major.minor.patch: solo cambian los identificadores de pre-release
+	return ChangePreRelease
+}
+
+// SameMajor indica si dos versiones pertenecen a la misma línea major
+func SameMajor(a, b string) bool {
+	versionA, errA := Parse(a)
+	versionB, errB := Parse(b)
+	return errA == nil && errB == nil && versionA.Major == versionB.Major
+}
+
+// SameMinor indica si dos versiones pertenecen a la misma línea major.minor
+func SameMinor(a, b string) bool {
+	versionA, errA := Parse(a)
+	versionB, errB := Parse(b)
+	return errA == nil && errB == nil && versionA.Major == versionB.Major && versionA.Minor == versionB.Minor
+}
+
 // IsNewer compara dos versiones y retorna true si newVersion es más nueva que currentVersion
 func IsNewer(currentVersion, newVersion string) bool {
 	if newVersion == "unknown" || newVersion == "internal" || newVersion == "managed" {
 		return false
 	}
 
-	current := parseVersion(currentVersion)
-	newer := parseVersion(newVersion)
+	return Compare(newVersion, currentVersion) > 0
+}
+
+// IsPreRelease indica si una string de versión es una pre-release válida
+func IsPreRelease(s string) bool {
+	v, err := Parse(s)
+	return err == nil && v.IsPreRelease()
+}
 
-	// Comparar major, minor, patch
-	for i := 0; i < 3; i++ {
-		if newer[i] > current[i] {
-			return true
-		} else if newer[i] < current[i] {
-			return false
+// Compare compara dos versiones y retorna:
+// -1 si a < b
+//  0 si a == b
+//  1 si a > b
+//
+// Las versiones que no son SemVer válidas se ordenan antes que cualquier versión válida
+// y se comparan entre sí lexicográficamente.
+func Compare(a, b string) int {
+	versionA, errA := Parse(a)
+	versionB, errB := Parse(b)
+
+	switch {
+	case errA != nil && errB != nil:
+		return strings.Compare(a, b)
+	case errA != nil:
+		return -1
+	case errB != nil:
+		return 1
+	}
+
+	return versionA.Compare(versionB)
+}
+
+// comparePreRelease aplica las reglas de precedencia de pre-release de SemVer 2.0
+func comparePreRelease(a, b []string) int {
+	// Una versión sin pre-release tiene mayor precedencia que una con pre-release
+	switch {
+	case len(a) == 0 && len(b) == 0:
+		return 0
+	case len(a) == 0:
+		return 1
+	case len(b) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if c := compareIdentifier(a[i], b[i]); c != 0 {
+			return c
 		}
 	}
 
-	// Las versiones son iguales
-	return false
+	// Un conjunto mayor de identificadores tiene mayor precedencia si los anteriores son iguales
+	return compareUint(uint64(len(a)), uint64(len(b)))
 }
 
-// parseVersion extrae los números de versión de una string
-func parseVersion(version string) []int {
-	// Remover prefijos comunes como v, V
-	cleanVersion := strings.TrimPrefix(strings.TrimPrefix(version, "v"), "V")
+// compareIdentifier compara un identificador de pre-release.
+// Los numéricos se comparan numéricamente y siempre tienen menor precedencia que los alfanuméricos.
+func compareIdentifier(a, b string) int {
+	aNum, bNum := isNumeric(a), isNumeric(b)
 
-	// Regex para extraer números separados por puntos
-	re := regexp.MustCompile(`^(\d+)(?:\.(\d+))?(?:\.(\d+))?`)
-	matches := re.FindStringSubmatch(cleanVersion)
+	switch {
+	case aNum && bNum:
+		// Sin ceros a la izquierda, la longitud decide antes que el contenido
+		if c := compareUint(uint64(len(a)), uint64(len(b))); c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
+	case aNum:
+		return -1
+	case bNum:
+		return 1
+	}
 
-	if matches == nil {
-		return []int{0, 0, 0}
+	return strings.Compare(a, b)
+}
+
+// validateIdentifiers valida una lista de identificadores separados por puntos
+func validateIdentifiers(s string, preRelease bool) error {
+	if s == "" {
+		return fmt.Errorf("empty identifier list")
 	}
 
-	result := make([]int, 3)
-	for i := 1; i < len(matches) && i <= 3; i++ {
-		if matches[i] != "" {
-			if num, err := strconv.Atoi(matches[i]); err == nil {
-				result[i-1] = num
+	for _, id := range strings.Split(s, ".") {
+		if id == "" {
+			return fmt.Errorf("empty identifier")
+		}
+		for _, r := range id {
+			if !isIdentifierRune(r) {
+				return fmt.Errorf("invalid character %q in identifier %q", r, id)
 			}
 		}
+		// Los identificadores numéricos de pre-release no pueden tener ceros a la izquierda
+		if preRelease && len(id) > 1 && id[0] == '0' && isNumeric(id) {
+			return fmt.Errorf("numeric identifier %q has leading zeros", id)
+		}
 	}
 
-	return result
+	return nil
 }
 
-// Compare compara dos versiones y retorna:
-// -1 si a < b
-//  0 si a == b
-//  1 si a > b
-func Compare(a, b string) int {
-	versionA := parseVersion(a)
-	versionB := parseVersion(b)
-
-	for i := 0; i < 3; i++ {
-		if versionA[i] < versionB[i] {
-			return -1
-		} else if versionA[i] > versionB[i] {
-			return 1
+// isIdentifierRune verifica si un caracter es válido en un identificador [0-9A-Za-z-]
+func isIdentifierRune(r rune) bool {
+	return (r >= '0' && r <= '9') || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || r == '-'
+}
+
+// isNumeric verifica si una string contiene únicamente dígitos
+func isNumeric(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
 		}
 	}
+	return true
+}
 
+// compareUint compara dos enteros sin signo
+func compareUint(a, b uint64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
 	return 0
-}
\ No newline at end of file
+}
@@ -0,0 +1,224 @@
+package version
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{input: "1.2.3", want: "1.2.3"},
+		{input: "v1.2.3", want: "1.2.3"},
+		{input: "V1.2.3", want: "1.2.3"},
+		{input: " 1.2.3 ", want: "1.2.3"},
+		{input: "1.2", want: "1.2.0"},
+		{input: "1", want: "1.0.0"},
+		{input: "1.2.3-rc.1", want: "1.2.3-rc.1"},
+		{input: "1.2.3+build.5", want: "1.2.3+build.5"},
+		{input: "1.2.3-alpha.1+build.5", want: "1.2.3-alpha.1+build.5"},
+		{input: "1.2.3-0", want: "1.2.3-0"},
+		{input: "1.2.3-x-y-z.--", want: "1.2.3-x-y-z.--"},
+		{input: "1.2.3+001", want: "1.2.3+001"},
+		{input: "v1.27.6+rke2r1", want: "1.27.6+rke2r1"},
+
+		{input: "", wantErr: true},
+		{input: "v", wantErr: true},
+		{input: "1.2.3.4", wantErr: true},
+		{input: "1..3", wantErr: true},
+		{input: "1.2.", wantErr: true},
+		{input: "a.b.c", wantErr: true},
+		{input: "1.2.x", wantErr: true},
+		{input: "1.2.3-", wantErr: true},
+		{input: "1.2.3-rc..1", wantErr: true},
+		{input: "1.2.3-rc.", wantErr: true},
+		{input: "1.2.3+", wantErr: true},
+		{input: "1.2.3+build..5", wantErr: true},
+		{input: "1.2.3-01", wantErr: true},
+		{input: "1.2.3-rc.007", wantErr: true},
+		{input: "1.2.3-rc_1", wantErr: true},
+		{input: "1.2.3+build@5", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			v, err := Parse(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Parse(%q) = %s, want error", tt.input, v)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse(%q) unexpected error: %v", tt.input, err)
+			}
+			if got := v.String(); got != tt.want {
+				t.Errorf("Parse(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		// Core numérico
+		{"1.0.0", "1.0.0", 0},
+		{"1.0.0", "2.0.0", -1},
+		{"2.0.0", "2.1.0", -1},
+		{"2.1.0", "2.1.1", -1},
+		{"1.10.0", "1.9.0", 1},
+		{"1.0.10", "1.0.9", 1},
+
+		// Prefijo v y componentes omitidos
+		{"v1.2.3", "1.2.3", 0},
+		{"1.2", "1.2.0", 0},
+		{"1", "1.0.0", 0},
+		{"v1.3", "1.2.9", 1},
+
+		// Pre-release con menor precedencia que la versión normal
+		{"1.2.3-rc.1", "1.2.3", -1},
+		{"1.2.3", "1.2.3-rc.1", 1},
+
+		// Build metadata sin efecto en la precedencia
+		{"1.2.3+build.5", "1.2.3", 0},
+		{"1.2.3+build.5", "1.2.3+build.6", 0},
+		{"1.2.3-rc.1+a", "1.2.3-rc.1+b", 0},
+
+		// Identificadores numéricos comparados numéricamente
+		{"1.0.0-rc.2", "1.0.0-rc.10", -1},
+		{"1.0.0-2", "1.0.0-10", -1},
+		// Identificadores numéricos con menor precedencia que los alfanuméricos
+		{"1.0.0-1", "1.0.0-alpha", -1},
+		{"1.0.0-alpha.1", "1.0.0-alpha.beta", -1},
+		// Identificadores alfanuméricos comparados en ASCII
+		{"1.0.0-Alpha", "1.0.0-alpha", -1},
+		{"1.0.0-alpha-2", "1.0.0-alpha-10", 1},
+		// Más identificadores con mayor precedencia si los anteriores son iguales
+		{"1.0.0-alpha", "1.0.0-alpha.1", -1},
+
+		// Versiones inválidas ordenadas antes de las válidas y entre sí lexicográficamente
+		{"unknown", "1.0.0", -1},
+		{"1.0.0", "unknown", 1},
+		{"abc", "abd", -1},
+		{"abc", "abc", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_vs_"+tt.b, func(t *testing.T) {
+			if got := Compare(tt.a, tt.b); got != tt.want {
+				t.Errorf("Compare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+			}
+			if got := Compare(tt.b, tt.a); got != -tt.want {
+				t.Errorf("Compare(%q, %q) = %d, want %d", tt.b, tt.a, got, -tt.want)
+			}
+		})
+	}
+}
+
+// TestPrecedenceChain verifica el ejemplo de precedencia de la sección 11 de SemVer 2.0
+func TestPrecedenceChain(t *testing.T) {
+	chain := []string{
+		"1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"1.0.0-alpha.beta",
+		"1.0.0-beta",
+		"1.0.0-beta.2",
+		"1.0.0-beta.11",
+		"1.0.0-rc.1",
+		"1.0.0",
+		"2.0.0",
+		"2.1.0",
+		"2.1.1",
+	}
+
+	for i := range chain {
+		for j := range chain {
+			want := compareUint(uint64(i), uint64(j))
+			if got := Compare(chain[i], chain[j]); got != want {
+				t.Errorf("Compare(%q, %q) = %d, want %d", chain[i], chain[j], got, want)
+			}
+		}
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		current, newer string
+		want           Change
+	}{
+		{"1.2.3", "2.0.0", ChangeMajor},
+		{"1.2.3", "1.3.0", ChangeMinor},
+		{"1.2.3", "1.2.4", ChangePatch},
+		{"1.2.3-rc.1", "1.2.3-rc.2", ChangePreRelease},
+		{"1.2.3-rc.1", "1.2.3", ChangePreRelease},
+		{"v1.2", "1.2.1", ChangePatch},
+		{"1.2.3", "1.2.3", ChangeNone},
+		{"1.2.3", "1.2.3+build.1", ChangeNone},
+		{"1.2.3", "1.2.2", ChangeNone},
+		{"2.0.0", "1.9.9", ChangeNone},
+		{"unknown", "1.0.0", ChangeNone},
+		{"1.0.0", "unknown", ChangeNone},
+		{"1.0.0", "managed", ChangeNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.current+"_to_"+tt.newer, func(t *testing.T) {
+			if got := Classify(tt.current, tt.newer); got != tt.want {
+				t.Errorf("Classify(%q, %q) = %q, want %q", tt.current, tt.newer, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsNewer(t *testing.T) {
+	tests := []struct {
+		current, newer string
+		want           bool
+	}{
+		{"1.2.3", "1.2.4", true},
+		{"1.2.3-rc.1", "1.2.3", true},
+		{"1.2.3", "1.2.3-rc.1", false},
+		{"1.2.3", "1.2.3", false},
+		{"1.2.3", "1.2.3+build.5", false},
+		{"1.2.3", "v1.10.0", true},
+		{"1.2.3", "unknown", false},
+		{"1.2.3", "internal", false},
+		{"1.2.3", "managed", false},
+		{"unknown", "1.0.0", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.current+"_to_"+tt.newer, func(t *testing.T) {
+			if got := IsNewer(tt.current, tt.newer); got != tt.want {
+				t.Errorf("IsNewer(%q, %q) = %v, want %v", tt.current, tt.newer, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSameLine(t *testing.T) {
+	tests := []struct {
+		a, b                 string
+		sameMajor, sameMinor bool
+	}{
+		{"1.2.3", "1.2.9", true, true},
+		{"1.2.3", "1.3.0", true, false},
+		{"1.2.3", "2.2.3", false, false},
+		{"v1.2", "1.2.5-rc.1", true, true},
+		{"1.2.3", "unknown", false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"_"+tt.b, func(t *testing.T) {
+			if got := SameMajor(tt.a, tt.b); got != tt.sameMajor {
+				t.Errorf("SameMajor(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.sameMajor)
+			}
+			if got := SameMinor(tt.a, tt.b); got != tt.sameMinor {
+				t.Errorf("SameMinor(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.sameMinor)
+			}
+		})
+	}
+}